@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"time"
@@ -8,6 +9,8 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/jeremy-kr/ccfg/internal/scanner"
 	"github.com/jeremy-kr/ccfg/internal/tui"
+	"github.com/jeremy-kr/ccfg/internal/usage"
+	"github.com/jeremy-kr/ccfg/internal/watcher"
 )
 
 var (
@@ -17,7 +20,26 @@ var (
 )
 
 func main() {
-	if len(os.Args) > 1 && (os.Args[1] == "--version" || os.Args[1] == "-v") {
+	var (
+		showVersion  bool
+		poll         bool
+		pollInterval time.Duration
+		since        string
+		until        string
+		rangeExpr    string
+		tz           string
+	)
+	flag.BoolVar(&showVersion, "version", false, "print version and exit")
+	flag.BoolVar(&showVersion, "v", false, "print version and exit (shorthand)")
+	flag.BoolVar(&poll, "poll", false, "detect file changes by polling instead of fsnotify")
+	flag.DurationVar(&pollInterval, "poll-interval", watcher.DefaultPollInterval, "polling interval (used with --poll or when fsnotify is unavailable)")
+	flag.StringVar(&since, "since", "", "show usage from this date on (YYYY-MM-DD, YYYY-MM, YYYY or RFC 3339)")
+	flag.StringVar(&until, "until", "", "show usage through this date, inclusive")
+	flag.StringVar(&rangeExpr, "range", "", `show usage within a range: "since..until" or a preset like "last month"`)
+	flag.StringVar(&tz, "tz", "", "IANA time zone for dates and days (default: the local zone)")
+	flag.Parse()
+
+	if showVersion {
 		short := commit
 		if len(short) > 7 {
 			short = short[:7]
@@ -26,6 +48,20 @@ func main() {
 		return
 	}
 
+	if tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid --tz: %v\n", err)
+			os.Exit(2)
+		}
+		time.Local = loc
+	}
+	dateRange, err := parseDateRange(since, until, rangeExpr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(2)
+	}
+
 	s := scanner.New("")
 
 	start := time.Now()
@@ -37,7 +73,10 @@ func main() {
 		os.Exit(1)
 	}
 
-	m := tui.NewModel(result, scanDuration, s)
+	m := tui.NewModel(result, scanDuration, s, tui.Options{
+		Watch: watcher.Options{Poll: poll, PollInterval: pollInterval},
+		Range: dateRange,
+	})
 	p := tea.NewProgram(m, tea.WithAltScreen())
 
 	if _, err := p.Run(); err != nil {
@@ -45,3 +84,21 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// parseDateRange combines the --since, --until and --range flags into a date range.
+func parseDateRange(since, until, rangeExpr string) (usage.DateRange, error) {
+	if rangeExpr != "" && (since != "" || until != "") {
+		return usage.DateRange{}, fmt.Errorf("--range cannot be combined with --since or --until")
+	}
+	if rangeExpr == "" {
+		if since == "" && until == "" {
+			return usage.DateRange{}, nil
+		}
+		rangeExpr = since + ".." + until
+	}
+	r, err := usage.ParseRange(rangeExpr, time.Now())
+	if err != nil {
+		return usage.DateRange{}, fmt.Errorf("invalid date range: %w", err)
+	}
+	return r, nil
+}
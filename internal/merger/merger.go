@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"reflect"
 	"sort"
 	"strings"
 
@@ -21,17 +22,50 @@ type SourcedValue struct {
 // MergedConfig holds the result of merging settings from all scopes.
 type MergedConfig struct {
 	Values []SourcedValue // Flat list of key-value pairs
+
+	layers map[string]map[string]SourcedValue // Flattened values per settings file path
 }
 
 // Merge merges JSON config files from a ScanResult according to priority.
 // Priority: Project > User > Managed.
 func Merge(result *model.ScanResult) *MergedConfig {
+	mc := &MergedConfig{}
+	mc.Update(result, nil)
+	return mc
+}
+
+// Update re-merges result after the given files changed. Only changed settings
+// files are re-read; the others reuse their cached values. A nil changed re-reads all files.
+func (mc *MergedConfig) Update(result *model.ScanResult, changed []string) {
+	dirty := make(map[string]bool, len(changed))
+	for _, p := range changed {
+		dirty[p] = true
+	}
+
+	prev := mc.layers
+	mc.layers = make(map[string]map[string]SourcedValue)
 	merged := make(map[string]SourcedValue)
 
 	// Apply from lowest priority first (later entries overwrite earlier ones)
-	applyScope(merged, result.Managed, model.ScopeManaged)
-	applyScope(merged, result.User, model.ScopeUser)
-	applyScope(merged, result.Project, model.ScopeProject)
+	for _, files := range [][]model.ConfigFile{result.Managed, result.User, result.Project} {
+		for _, f := range files {
+			if !f.Exists || f.FileType != model.FileTypeJSON || f.IsDir {
+				continue
+			}
+			if f.Category != model.CategorySettings {
+				continue
+			}
+
+			layer, ok := prev[f.Path]
+			if !ok || changed == nil || dirty[f.Path] {
+				layer = loadLayer(f)
+			}
+			mc.layers[f.Path] = layer
+			for k, v := range layer {
+				merged[k] = v
+			}
+		}
+	}
 
 	// Sort by key
 	values := make([]SourcedValue, 0, len(merged))
@@ -41,32 +75,25 @@ func Merge(result *model.ScanResult) *MergedConfig {
 	sort.Slice(values, func(i, j int) bool {
 		return values[i].Key < values[j].Key
 	})
-
-	return &MergedConfig{Values: values}
+	mc.Values = values
 }
 
-func applyScope(merged map[string]SourcedValue, files []model.ConfigFile, scope model.Scope) {
-	for _, f := range files {
-		if !f.Exists || f.FileType != model.FileTypeJSON || f.IsDir {
-			continue
-		}
-		if f.Category != model.CategorySettings {
-			continue
-		}
-
-		data, err := os.ReadFile(f.Path)
-		if err != nil {
-			continue
-		}
-
-		cleaned := parser.StripJSONC(string(data))
-		var obj map[string]any
-		if err := json.Unmarshal([]byte(cleaned), &obj); err != nil {
-			continue
-		}
+// loadLayer reads a settings file and flattens it. Unreadable or invalid files yield an empty layer.
+func loadLayer(f model.ConfigFile) map[string]SourcedValue {
+	layer := make(map[string]SourcedValue)
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		return layer
+	}
 
-		flatten("", obj, scope, merged)
+	cleaned := parser.StripJSONC(string(data))
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(cleaned), &obj); err != nil {
+		return layer
 	}
+
+	flatten("", obj, f.Scope, layer)
+	return layer
 }
 
 func flatten(prefix string, obj map[string]any, scope model.Scope, out map[string]SourcedValue) {
@@ -85,6 +112,48 @@ func flatten(prefix string, obj map[string]any, scope model.Scope, out map[strin
 	}
 }
 
+// ValueChange describes an effective setting that differs between two merges.
+type ValueChange struct {
+	Key string        // Dot-notation path
+	Old *SourcedValue // nil when the key was added
+	New *SourcedValue // nil when the key was removed
+}
+
+// Diff compares two merged value lists and returns the keys whose effective
+// value or origin scope changed, sorted by key.
+func Diff(before, after []SourcedValue) []ValueChange {
+	old := make(map[string]SourcedValue, len(before))
+	for _, v := range before {
+		old[v.Key] = v
+	}
+
+	var changes []ValueChange
+	seen := make(map[string]bool, len(after))
+	for _, v := range after {
+		v := v
+		seen[v.Key] = true
+		prev, ok := old[v.Key]
+		if !ok {
+			changes = append(changes, ValueChange{Key: v.Key, New: &v})
+			continue
+		}
+		if prev.Scope != v.Scope || !reflect.DeepEqual(prev.Value, v.Value) {
+			changes = append(changes, ValueChange{Key: v.Key, Old: &prev, New: &v})
+		}
+	}
+	for _, v := range before {
+		v := v
+		if !seen[v.Key] {
+			changes = append(changes, ValueChange{Key: v.Key, Old: &v})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
 // Render formats the merged config into a human-readable string.
 func (mc *MergedConfig) Render() string {
 	if len(mc.Values) == 0 {
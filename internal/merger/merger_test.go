@@ -0,0 +1,238 @@
+package merger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jeremy-kr/ccfg/internal/model"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// settingsResult returns a scan result with a user and a project settings file.
+func settingsResult(t *testing.T) (result *model.ScanResult, user, project string) {
+	t.Helper()
+	dir := t.TempDir()
+	user = filepath.Join(dir, "user-settings.json")
+	project = filepath.Join(dir, "project-settings.json")
+	writeFile(t, user, `{"model": "sonnet", "env": {"A": "1"}}`)
+	writeFile(t, project, `{
+		// Project settings win over user settings.
+		"model": "opus"
+	}`)
+	file := func(path string, scope model.Scope) model.ConfigFile {
+		return model.ConfigFile{Path: path, Scope: scope, FileType: model.FileTypeJSON, Category: model.CategorySettings, Exists: true}
+	}
+	result = &model.ScanResult{
+		User:    []model.ConfigFile{file(user, model.ScopeUser)},
+		Project: []model.ConfigFile{file(project, model.ScopeProject)},
+	}
+	return result, user, project
+}
+
+func byKey(values []SourcedValue) map[string]SourcedValue {
+	m := make(map[string]SourcedValue, len(values))
+	for _, v := range values {
+		m[v.Key] = v
+	}
+	return m
+}
+
+func TestMerge_Precedence(t *testing.T) {
+	result, _, _ := settingsResult(t)
+	got := byKey(Merge(result).Values)
+	want := map[string]SourcedValue{
+		"model": {Key: "model", Value: "opus", Scope: model.ScopeProject},
+		"env.A": {Key: "env.A", Value: "1", Scope: model.ScopeUser},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge = %+v, want %+v", got, want)
+	}
+}
+
+func TestMergedConfig_Update(t *testing.T) {
+	tests := []struct {
+		name string
+		// edit changes the files on disk or the scan result and returns the paths reported as changed.
+		edit         func(t *testing.T, result *model.ScanResult, user, project string) []string
+		want         map[string]SourcedValue
+		matchesMerge bool // Whether the result equals a full Merge of the edited files
+	}{
+		{
+			name: "unchanged layers are reused",
+			edit: func(t *testing.T, _ *model.ScanResult, user, project string) []string {
+				writeFile(t, user, `{"model": "haiku", "env": {"A": "2"}}`)
+				writeFile(t, project, `{"model": "opus", "theme": "dark"}`)
+				return []string{project}
+			},
+			want: map[string]SourcedValue{
+				"model": {Key: "model", Value: "opus", Scope: model.ScopeProject},
+				"env.A": {Key: "env.A", Value: "1", Scope: model.ScopeUser},
+				"theme": {Key: "theme", Value: "dark", Scope: model.ScopeProject},
+			},
+		},
+		{
+			name: "deleted file",
+			edit: func(t *testing.T, _ *model.ScanResult, _, project string) []string {
+				if err := os.Remove(project); err != nil {
+					t.Fatal(err)
+				}
+				return []string{project}
+			},
+			want: map[string]SourcedValue{
+				"model": {Key: "model", Value: "sonnet", Scope: model.ScopeUser},
+				"env.A": {Key: "env.A", Value: "1", Scope: model.ScopeUser},
+			},
+			matchesMerge: true,
+		},
+		{
+			name: "deleted file rescanned",
+			edit: func(t *testing.T, result *model.ScanResult, _, project string) []string {
+				if err := os.Remove(project); err != nil {
+					t.Fatal(err)
+				}
+				result.Project[0].Exists = false
+				return []string{project}
+			},
+			want: map[string]SourcedValue{
+				"model": {Key: "model", Value: "sonnet", Scope: model.ScopeUser},
+				"env.A": {Key: "env.A", Value: "1", Scope: model.ScopeUser},
+			},
+			matchesMerge: true,
+		},
+		{
+			name: "lower scope change keeps precedence",
+			edit: func(t *testing.T, _ *model.ScanResult, user, _ string) []string {
+				writeFile(t, user, `{"model": "haiku", "theme": "light"}`)
+				return []string{user}
+			},
+			want: map[string]SourcedValue{
+				"model": {Key: "model", Value: "opus", Scope: model.ScopeProject},
+				"theme": {Key: "theme", Value: "light", Scope: model.ScopeUser},
+			},
+			matchesMerge: true,
+		},
+		{
+			name: "higher scope removes an override",
+			edit: func(t *testing.T, _ *model.ScanResult, _, project string) []string {
+				writeFile(t, project, `{}`)
+				return []string{project}
+			},
+			want: map[string]SourcedValue{
+				"model": {Key: "model", Value: "sonnet", Scope: model.ScopeUser},
+				"env.A": {Key: "env.A", Value: "1", Scope: model.ScopeUser},
+			},
+			matchesMerge: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, user, project := settingsResult(t)
+			mc := Merge(result)
+			mc.Update(result, tt.edit(t, result, user, project))
+
+			if got := byKey(mc.Values); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Update = %+v, want %+v", got, tt.want)
+			}
+			if tt.matchesMerge {
+				if full := Merge(result).Values; !reflect.DeepEqual(mc.Values, full) {
+					t.Errorf("Update = %+v, full Merge = %+v", mc.Values, full)
+				}
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	user := func(key string, value any) SourcedValue {
+		return SourcedValue{Key: key, Value: value, Scope: model.ScopeUser}
+	}
+	project := func(key string, value any) SourcedValue {
+		return SourcedValue{Key: key, Value: value, Scope: model.ScopeProject}
+	}
+	ptr := func(v SourcedValue) *SourcedValue { return &v }
+
+	tests := []struct {
+		name          string
+		before, after []SourcedValue
+		want          []ValueChange
+	}{
+		{
+			name:   "added",
+			before: []SourcedValue{user("model", "opus")},
+			after:  []SourcedValue{user("model", "opus"), user("theme", "dark")},
+			want:   []ValueChange{{Key: "theme", New: ptr(user("theme", "dark"))}},
+		},
+		{
+			name:   "removed",
+			before: []SourcedValue{user("model", "opus"), user("theme", "dark")},
+			after:  []SourcedValue{user("model", "opus")},
+			want:   []ValueChange{{Key: "theme", Old: ptr(user("theme", "dark"))}},
+		},
+		{
+			name:   "value changed",
+			before: []SourcedValue{user("permissions.allow", []any{"Read"})},
+			after:  []SourcedValue{user("permissions.allow", []any{"Read", "Bash"})},
+			want: []ValueChange{{
+				Key: "permissions.allow",
+				Old: ptr(user("permissions.allow", []any{"Read"})),
+				New: ptr(user("permissions.allow", []any{"Read", "Bash"})),
+			}},
+		},
+		{
+			name:   "scope changed",
+			before: []SourcedValue{user("model", "opus")},
+			after:  []SourcedValue{project("model", "opus")},
+			want:   []ValueChange{{Key: "model", Old: ptr(user("model", "opus")), New: ptr(project("model", "opus"))}},
+		},
+		{
+			name:   "unchanged",
+			before: []SourcedValue{user("model", "opus"), user("env.A", float64(1))},
+			after:  []SourcedValue{user("env.A", float64(1)), user("model", "opus")},
+		},
+		{
+			name:   "sorted by key",
+			before: []SourcedValue{user("b", 1), user("c", 1)},
+			after:  []SourcedValue{user("c", 2), user("a", 1)},
+			want: []ValueChange{
+				{Key: "a", New: ptr(user("a", 1))},
+				{Key: "b", Old: ptr(user("b", 1))},
+				{Key: "c", Old: ptr(user("c", 1)), New: ptr(user("c", 2))},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff = %s, want %s", describe(got), describe(tt.want))
+			}
+		})
+	}
+}
+
+// describe formats changes for failure messages, since they hold pointers.
+func describe(changes []ValueChange) string {
+	s := "["
+	for i, c := range changes {
+		if i > 0 {
+			s += ", "
+		}
+		s += c.Key + ":"
+		if c.Old != nil {
+			s += fmt.Sprintf(" %v (%s)", c.Old.Value, c.Old.Scope)
+		}
+		s += " ->"
+		if c.New != nil {
+			s += fmt.Sprintf(" %v (%s)", c.New.Value, c.New.Scope)
+		}
+	}
+	return s + "]"
+}
@@ -86,19 +86,68 @@ func (c ConfigCategory) String() string {
 	}
 }
 
+// Severity represents how serious a diagnostic is.
+type Severity int
+
+const (
+	SeverityWarning Severity = iota // Suspicious but accepted (unknown or deprecated keys)
+	SeverityError                   // Invalid (wrong type, invalid enum value, syntax error)
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "Warning"
+	case SeverityError:
+		return "Error"
+	default:
+		return "Unknown"
+	}
+}
+
+// Diagnostic represents a single validation finding within a config file.
+type Diagnostic struct {
+	Severity Severity // How serious the finding is
+	Path     string   // Key path (e.g. "permissions.allow[0]"), empty for file-level findings
+	Line     int      // 1-based line number in the file (0 if unknown)
+	Message  string   // Human-readable description
+}
+
+// SecretFinding represents a likely credential found in a config file.
+type SecretFinding struct {
+	Rule   string // Detection rule (e.g. "GitHub token", "high-entropy value")
+	Line   int    // 1-based line number in the file
+	Masked string // Matched value with most characters masked
+}
+
 // ConfigFile represents a single scanned config file.
 type ConfigFile struct {
-	Path        string         // Absolute path
-	Scope       Scope          // Owning scope
-	FileType    FileType       // File format
-	Category    ConfigCategory // Functional category
-	Exists      bool           // Whether the file exists
-	IsDir       bool           // Whether it is a directory (commands/, skills/)
-	IsVirtual   bool           // Whether it is a virtual node (section inside JSON)
-	Size        int64          // Size in bytes (when exists)
-	ModTime     time.Time      // Last modification time (when exists)
-	Description string         // Human-readable description
-	Children    []ConfigFile   // Child files when this is a directory
+	Path        string          // Absolute path
+	Scope       Scope           // Owning scope
+	FileType    FileType        // File format
+	Category    ConfigCategory  // Functional category
+	Exists      bool            // Whether the file exists
+	IsDir       bool            // Whether it is a directory (commands/, skills/)
+	IsVirtual   bool            // Whether it is a virtual node (section inside JSON)
+	Size        int64           // Size in bytes (when exists)
+	ModTime     time.Time       // Last modification time (when exists)
+	Description string          // Human-readable description
+	Children    []ConfigFile    // Child files when this is a directory
+	Diagnostics []Diagnostic    // Schema validation findings (JSON files only)
+	Secrets     []SecretFinding // Likely credentials found in the file content
+	GitTracked  bool            // Whether the file is tracked by git (checked only when Secrets is non-empty)
+}
+
+// DiagnosticCounts returns the number of errors and warnings attached to the file.
+func (f *ConfigFile) DiagnosticCounts() (errors, warnings int) {
+	for _, d := range f.Diagnostics {
+		if d.Severity == SeverityError {
+			errors++
+		} else {
+			warnings++
+		}
+	}
+	return
 }
 
 // ScanResult represents the complete scan result.
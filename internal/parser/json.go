@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/alecthomas/chroma/v2/quick"
 )
@@ -41,11 +42,35 @@ func highlightJSON(src string) string {
 
 // StripJSONC strips comments and trailing commas from JSONC.
 func StripJSONC(s string) string {
+	return stripJSONC(s, false)
+}
+
+// BlankJSONC is like StripJSONC but replaces comments and trailing commas with
+// spaces (keeping newlines), so byte offsets and line numbers match the original.
+func BlankJSONC(s string) string {
+	return stripJSONC(s, true)
+}
+
+func stripJSONC(s string, keepOffsets bool) string {
 	var result strings.Builder
 	runes := []rune(s)
 	i := 0
 	inString := false
 
+	// blank replaces runes[from:to] with same-width whitespace when keepOffsets is set.
+	blank := func(from, to int) {
+		if !keepOffsets {
+			return
+		}
+		for _, r := range runes[from:min(to, len(runes))] {
+			if r == '\n' {
+				result.WriteRune(r)
+			} else {
+				result.WriteString(strings.Repeat(" ", utf8.RuneLen(r)))
+			}
+		}
+	}
+
 	for i < len(runes) {
 		ch := runes[i]
 
@@ -72,19 +97,23 @@ func StripJSONC(s string) string {
 
 		// Single-line comment
 		if ch == '/' && i+1 < len(runes) && runes[i+1] == '/' {
+			start := i
 			for i < len(runes) && runes[i] != '\n' {
 				i++
 			}
+			blank(start, i)
 			continue
 		}
 
 		// Block comment
 		if ch == '/' && i+1 < len(runes) && runes[i+1] == '*' {
+			start := i
 			i += 2
 			for i+1 < len(runes) && !(runes[i] == '*' && runes[i+1] == '/') {
 				i++
 			}
 			i += 2 // Skip past */
+			blank(start, i)
 			continue
 		}
 
@@ -97,6 +126,7 @@ func StripJSONC(s string) string {
 			}
 			if j < len(runes) && (runes[j] == ']' || runes[j] == '}') {
 				// Omit trailing comma
+				blank(i, i+1)
 				i++
 				continue
 			}
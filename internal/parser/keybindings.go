@@ -0,0 +1,202 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// Keybinding represents a single chord → action binding from keybindings.json.
+type Keybinding struct {
+	Context       string // Binding context (e.g. "Global", "Chat").
+	Chord         string // Key chord as written (e.g. "ctrl+k ctrl+s").
+	Action        string // Action name (empty when Unbound).
+	Unbound       bool   // Whether the chord is explicitly unbound (null).
+	Duplicate     bool   // Whether the chord is bound more than once in the same context.
+	UnknownAction bool   // Whether the action is not a known Claude Code action.
+	Shadows       string // Terminal default the chord shadows (empty if none).
+}
+
+// knownKeybindingActions lists the action names Claude Code accepts.
+// "command:<name>" actions (slash command shortcuts) are accepted separately.
+var knownKeybindingActions = stringSet(
+	"app:interrupt", "app:exit", "app:toggleTodos", "app:toggleTranscript",
+	"history:search", "history:previous", "history:next",
+	"chat:cancel", "chat:cycleMode", "chat:modelPicker", "chat:thinkingToggle",
+	"chat:submit", "chat:undo", "chat:externalEditor", "chat:stash", "chat:imagePaste",
+	"autocomplete:accept", "autocomplete:dismiss", "autocomplete:previous", "autocomplete:next",
+	"confirm:yes", "confirm:no", "confirm:previous", "confirm:next",
+	"confirm:nextField", "confirm:previousField", "confirm:cycleMode", "confirm:toggleExplanation",
+	"transcript:toggleShowAll", "transcript:exit",
+	"historySearch:next", "historySearch:accept", "historySearch:cancel", "historySearch:execute",
+	"task:background",
+	"theme:toggleSyntaxHighlighting",
+	"help:dismiss",
+	"tabs:next", "tabs:previous",
+	"attachments:next", "attachments:previous", "attachments:remove", "attachments:exit",
+	"footer:next", "footer:previous", "footer:openSelected", "footer:clearSelection",
+	"messageSelector:up", "messageSelector:down", "messageSelector:top", "messageSelector:bottom", "messageSelector:select",
+	"diff:dismiss", "diff:previousSource", "diff:nextSource", "diff:back",
+	"diff:viewDetails", "diff:previousFile", "diff:nextFile",
+	"modelPicker:decreaseEffort", "modelPicker:increaseEffort",
+	"select:next", "select:previous", "select:accept", "select:cancel",
+	"plugin:toggle", "plugin:install",
+	"settings:search", "settings:retry",
+)
+
+// terminalDefaults maps normalized chords to the terminal behavior they shadow.
+var terminalDefaults = map[string]string{
+	"ctrl+c":  "interrupt (SIGINT)",
+	"ctrl+d":  "end of input (EOF)",
+	"ctrl+z":  "suspend (SIGTSTP)",
+	"ctrl+\\": "quit (SIGQUIT)",
+	"ctrl+s":  "flow control (XOFF)",
+	"ctrl+q":  "flow control (XON)",
+	"ctrl+h":  "backspace",
+	"ctrl+i":  "tab",
+	"ctrl+j":  "newline",
+	"ctrl+m":  "enter",
+	"ctrl+[":  "escape",
+}
+
+// keyAliases maps alternative key and modifier names to a canonical form.
+var keyAliases = map[string]string{
+	"control": "ctrl",
+	"alt":     "meta",
+	"opt":     "meta",
+	"option":  "meta",
+	"command": "cmd",
+	"super":   "cmd",
+	"esc":     "escape",
+	"return":  "enter",
+}
+
+// modifierOrder fixes the order of modifiers in a normalized keystroke.
+var modifierOrder = map[string]int{"ctrl": 0, "meta": 1, "shift": 2, "cmd": 3}
+
+// ParseKeybindings parses raw keybindings.json (JSONC) content.
+// Format: {"bindings": [{"context": "Chat", "bindings": {"ctrl+e": "chat:externalEditor", "ctrl+u": null}}]}
+// Bindings keep file order so duplicates (including repeated JSON keys) can be reported.
+func ParseKeybindings(raw string) []Keybinding {
+	cleaned := StripJSONC(raw)
+
+	var obj struct {
+		Bindings []struct {
+			Context  string          `json:"context"`
+			Bindings json.RawMessage `json:"bindings"`
+		} `json:"bindings"`
+	}
+	if err := json.Unmarshal([]byte(cleaned), &obj); err != nil {
+		return nil
+	}
+
+	var result []Keybinding
+	seen := make(map[string]int) // context + normalized chord -> index of first binding
+	for _, block := range obj.Bindings {
+		pairs, err := orderedPairs(block.Bindings)
+		if err != nil {
+			continue
+		}
+		for _, p := range pairs {
+			kb := Keybinding{Context: block.Context, Chord: p.key}
+			if p.value == nil {
+				kb.Unbound = true
+			} else {
+				kb.Action = *p.value
+				kb.UnknownAction = !isKnownAction(kb.Action)
+			}
+
+			norm := NormalizeChord(kb.Chord)
+			kb.Shadows = terminalDefaults[norm]
+
+			id := kb.Context + "\x00" + norm
+			if first, ok := seen[id]; ok {
+				kb.Duplicate = true
+				result[first].Duplicate = true
+			} else {
+				seen[id] = len(result)
+			}
+			result = append(result, kb)
+		}
+	}
+	return result
+}
+
+// NormalizeChord returns a canonical form of a chord for comparison:
+// lowercase, aliases resolved and modifiers in a fixed order. Multi-key
+// sequences are separated by single spaces.
+func NormalizeChord(chord string) string {
+	strokes := strings.Fields(strings.ToLower(chord))
+	for i, stroke := range strokes {
+		parts := strings.Split(stroke, "+")
+		// A trailing "+" means the key itself is "+" (e.g. "ctrl++").
+		if strings.HasSuffix(stroke, "++") {
+			parts = append(strings.Split(strings.TrimSuffix(stroke, "++"), "+"), "+")
+		}
+		for j, p := range parts {
+			if alias, ok := keyAliases[p]; ok {
+				parts[j] = alias
+			}
+		}
+		key := parts[len(parts)-1]
+		mods := parts[:len(parts)-1]
+		sort.SliceStable(mods, func(a, b int) bool {
+			return modifierOrder[mods[a]] < modifierOrder[mods[b]]
+		})
+		strokes[i] = strings.Join(append(mods, key), "+")
+	}
+	return strings.Join(strokes, " ")
+}
+
+// isKnownAction reports whether action is a known action or a slash command shortcut.
+func isKnownAction(action string) bool {
+	return knownKeybindingActions[action] || strings.HasPrefix(action, "command:")
+}
+
+// stringSet builds a lookup set from a list of strings.
+func stringSet(items ...string) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[item] = true
+	}
+	return set
+}
+
+// keyValue is a single member of a JSON object whose value is a string or null.
+type keyValue struct {
+	key   string
+	value *string
+}
+
+// orderedPairs decodes a JSON object of string/null values in document order,
+// keeping repeated keys that json.Unmarshal would collapse.
+func orderedPairs(raw json.RawMessage) ([]keyValue, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := dec.Token(); err != nil { // opening '{'
+		return nil, err
+	}
+
+	var pairs []keyValue
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+
+		var rawValue json.RawMessage
+		if err := dec.Decode(&rawValue); err != nil {
+			return nil, err
+		}
+
+		var value *string
+		if err := json.Unmarshal(rawValue, &value); err != nil {
+			// Non-string values are kept verbatim so they surface as unknown actions.
+			s := compactJSON(rawValue)
+			value = &s
+		}
+		pairs = append(pairs, keyValue{key: key, value: value})
+	}
+	return pairs, nil
+}
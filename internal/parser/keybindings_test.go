@@ -0,0 +1,99 @@
+package parser
+
+import "testing"
+
+func TestParseKeybindings(t *testing.T) {
+	raw := `{
+		// JSONC comment test
+		"bindings": [
+			{
+				"context": "Chat",
+				"bindings": {
+					"ctrl+e": "chat:externalEditor",
+					"Ctrl+E": "chat:stash",
+					"ctrl+u": null,
+					"ctrl+z": "chat:undo",
+					"ctrl+k ctrl+s": "command:commit"
+				}
+			},
+			{
+				"context": "Global",
+				"bindings": {
+					"ctrl+e": "app:doesNotExist"
+				}
+			}
+		]
+	}`
+
+	bindings := ParseKeybindings(raw)
+	if len(bindings) != 6 {
+		t.Fatalf("expected 6 bindings, got %d", len(bindings))
+	}
+
+	byChord := func(ctx, chord string) Keybinding {
+		t.Helper()
+		for _, kb := range bindings {
+			if kb.Context == ctx && kb.Chord == chord {
+				return kb
+			}
+		}
+		t.Fatalf("binding %s/%s not found", ctx, chord)
+		return Keybinding{}
+	}
+
+	if !byChord("Chat", "ctrl+e").Duplicate || !byChord("Chat", "Ctrl+E").Duplicate {
+		t.Error("case variants of the same chord should be flagged as duplicates")
+	}
+	if byChord("Global", "ctrl+e").Duplicate {
+		t.Error("same chord in a different context is not a duplicate")
+	}
+	if !byChord("Global", "ctrl+e").UnknownAction {
+		t.Error("app:doesNotExist should be flagged as unknown")
+	}
+	if byChord("Chat", "ctrl+k ctrl+s").UnknownAction {
+		t.Error("command: actions should be accepted")
+	}
+	if kb := byChord("Chat", "ctrl+u"); !kb.Unbound || kb.UnknownAction {
+		t.Errorf("null binding should be unbound and not unknown: %+v", kb)
+	}
+	if byChord("Chat", "ctrl+z").Shadows == "" {
+		t.Error("ctrl+z should shadow the terminal suspend key")
+	}
+}
+
+func TestParseKeybindings_RepeatedJSONKey(t *testing.T) {
+	raw := `{"bindings": [{"context": "Chat", "bindings": {"ctrl+g": "chat:externalEditor", "ctrl+g": "chat:stash"}}]}`
+
+	bindings := ParseKeybindings(raw)
+	if len(bindings) != 2 {
+		t.Fatalf("expected both repeated keys to be kept, got %d", len(bindings))
+	}
+	if !bindings[0].Duplicate || !bindings[1].Duplicate {
+		t.Error("repeated JSON keys should be flagged as duplicates")
+	}
+}
+
+func TestParseKeybindings_Invalid(t *testing.T) {
+	if got := ParseKeybindings("not json"); got != nil {
+		t.Errorf("expected nil for invalid json, got %v", got)
+	}
+}
+
+func TestNormalizeChord(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Ctrl+E", "ctrl+e"},
+		{"shift+ctrl+a", "ctrl+shift+a"},
+		{"alt+x", "meta+x"},
+		{"Control+K  Control+S", "ctrl+k ctrl+s"},
+		{"esc", "escape"},
+		{"ctrl++", "ctrl++"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeChord(tt.input); got != tt.want {
+			t.Errorf("NormalizeChord(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
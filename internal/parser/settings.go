@@ -1,19 +1,75 @@
 package parser
 
-import "encoding/json"
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+)
 
 // HookEntry represents an individual event within the hooks section of settings.json.
 type HookEntry struct {
-	Event    string   // Event name (e.g. "SessionStart").
-	Count    int      // Number of registered commands.
-	Commands []string // List of command strings.
+	Event    string        // Event name (e.g. "SessionStart").
+	Count    int           // Number of registered commands.
+	Commands []string      // List of command strings.
+	Matchers []HookMatcher // Matcher groups in declaration order.
+}
+
+// HookMatcher represents a matcher group within a hook event.
+// Format: {"matcher": "Bash", "hooks": [{"type": "command", "command": "...", "timeout": 30}]}
+type HookMatcher struct {
+	Matcher string        // Tool name pattern (empty matches everything).
+	Hooks   []HookCommand // Hooks run when the matcher applies.
+}
+
+// HookCommand represents a single hook handler inside a matcher group.
+type HookCommand struct {
+	Type    string // Hook type (e.g. "command", "prompt").
+	Command string // Shell command (type "command").
+	Prompt  string // Prompt text (type "prompt").
+	Timeout int    // Timeout in seconds (0 when unset).
 }
 
 // MCPServerEntry represents an individual MCP server from settings.json or .mcp.json.
 type MCPServerEntry struct {
-	Name    string // Server name.
-	Type    string // Transport type (e.g. "stdio", "sse").
-	Command string // Execution command.
+	Name         string            // Server name.
+	Type         string            // Transport type ("stdio", "sse", "http"), declared or inferred.
+	TypeInferred bool              // Whether Type was inferred because the "type" key was omitted.
+	Command      string            // Execution command (stdio).
+	Args         []string          // Command arguments (stdio).
+	Env          map[string]string // Environment variables (stdio).
+	Cwd          string            // Working directory (stdio).
+	URL          string            // Endpoint URL (sse/http).
+	Headers      map[string]string // HTTP headers (sse/http).
+	Extra        map[string]string // Remaining keys as compact JSON (e.g. "timeout", "oauth").
+}
+
+// mcpKnownKeys lists the server keys mapped to dedicated MCPServerEntry fields.
+var mcpKnownKeys = map[string]bool{
+	"type": true, "command": true, "args": true, "env": true,
+	"cwd": true, "url": true, "headers": true,
+}
+
+// secretKeyPattern matches env/header names whose values are likely credentials.
+var secretKeyPattern = regexp.MustCompile(`(?i)(token|key|secret|passw(or)?d|auth|credential|cookie|session)`)
+
+// IsSecretKey reports whether an env or header name likely holds a credential.
+func IsSecretKey(name string) bool {
+	return secretKeyPattern.MatchString(name)
+}
+
+// MaskSecret replaces a secret value with a fixed-width mask, keeping a short
+// prefix for long values so different keys remain distinguishable.
+func MaskSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+	runes := []rune(value)
+	if len(runes) < 12 {
+		return "••••••••"
+	}
+	return string(runes[:4]) + "••••••••"
 }
 
 // ParseSettingsHooks parses the hooks key from raw settings.json (JSONC) content.
@@ -36,33 +92,70 @@ func ParseSettingsHooks(raw string) []HookEntry {
 	}
 
 	var entries []HookEntry
-	for event, cmdRaw := range hooks {
-		var cmds []map[string]any
-		if err := json.Unmarshal(cmdRaw, &cmds); err != nil {
+	for event, groupsRaw := range hooks {
+		var groups []map[string]json.RawMessage
+		if err := json.Unmarshal(groupsRaw, &groups); err != nil {
 			// May be a single object instead of an array
-			var single map[string]any
-			if err := json.Unmarshal(cmdRaw, &single); err != nil {
+			var single map[string]json.RawMessage
+			if err := json.Unmarshal(groupsRaw, &single); err != nil {
 				continue
 			}
-			cmds = []map[string]any{single}
+			groups = []map[string]json.RawMessage{single}
 		}
 
-		var commands []string
-		for _, c := range cmds {
-			if cmd, ok := c["command"].(string); ok {
-				commands = append(commands, cmd)
+		entry := HookEntry{Event: event}
+		for _, g := range groups {
+			m := parseHookMatcher(g)
+			for _, h := range m.Hooks {
+				if h.Command != "" {
+					entry.Commands = append(entry.Commands, h.Command)
+				}
 			}
+			entry.Count += len(m.Hooks)
+			entry.Matchers = append(entry.Matchers, m)
 		}
-
-		entries = append(entries, HookEntry{
-			Event:    event,
-			Count:    len(cmds),
-			Commands: commands,
-		})
+		entries = append(entries, entry)
 	}
 	return entries
 }
 
+// parseHookMatcher parses a single element of a hook event array.
+// Legacy flat entries ({"command": "..."}) are treated as a catch-all group with one hook.
+func parseHookMatcher(group map[string]json.RawMessage) HookMatcher {
+	var m HookMatcher
+	unmarshalField(group, "matcher", &m.Matcher)
+
+	hooksRaw, ok := group["hooks"]
+	if !ok {
+		m.Hooks = []HookCommand{parseHookCommand(group)}
+		return m
+	}
+
+	var hooks []map[string]json.RawMessage
+	if err := json.Unmarshal(hooksRaw, &hooks); err != nil {
+		return m
+	}
+	for _, h := range hooks {
+		m.Hooks = append(m.Hooks, parseHookCommand(h))
+	}
+	return m
+}
+
+// parseHookCommand parses a single hook handler object.
+func parseHookCommand(obj map[string]json.RawMessage) HookCommand {
+	var h HookCommand
+	unmarshalField(obj, "type", &h.Type)
+	unmarshalField(obj, "command", &h.Command)
+	unmarshalField(obj, "prompt", &h.Prompt)
+	var timeout float64
+	unmarshalField(obj, "timeout", &timeout)
+	h.Timeout = int(timeout)
+	if h.Type == "" && h.Command != "" {
+		h.Type = "command"
+	}
+	return h
+}
+
 // ParseMCPServers parses the mcpServers key from raw JSON/JSONC content.
 // Works with both settings.json and .mcp.json.
 func ParseMCPServers(raw string) []MCPServerEntry {
@@ -85,20 +178,93 @@ func ParseMCPServers(raw string) []MCPServerEntry {
 
 	var entries []MCPServerEntry
 	for name, srvRaw := range servers {
-		var srv map[string]any
+		var srv map[string]json.RawMessage
 		if err := json.Unmarshal(srvRaw, &srv); err != nil {
 			entries = append(entries, MCPServerEntry{Name: name})
 			continue
 		}
+		entries = append(entries, parseMCPServer(name, srv))
+	}
+	return entries
+}
 
-		entry := MCPServerEntry{Name: name}
-		if t, ok := srv["type"].(string); ok {
-			entry.Type = t
+// parseMCPServer parses a single server object and resolves its transport.
+func parseMCPServer(name string, srv map[string]json.RawMessage) MCPServerEntry {
+	entry := MCPServerEntry{Name: name}
+	unmarshalField(srv, "type", &entry.Type)
+	unmarshalField(srv, "command", &entry.Command)
+	unmarshalField(srv, "args", &entry.Args)
+	unmarshalField(srv, "cwd", &entry.Cwd)
+	unmarshalField(srv, "url", &entry.URL)
+	entry.Env = stringMap(srv["env"])
+	entry.Headers = stringMap(srv["headers"])
+
+	for k, v := range srv {
+		if mcpKnownKeys[k] {
+			continue
 		}
-		if c, ok := srv["command"].(string); ok {
-			entry.Command = c
+		if entry.Extra == nil {
+			entry.Extra = make(map[string]string)
 		}
-		entries = append(entries, entry)
+		entry.Extra[k] = compactJSON(v)
 	}
-	return entries
+
+	if entry.Type == "" {
+		entry.Type = inferMCPTransport(entry)
+		entry.TypeInferred = entry.Type != ""
+	}
+	return entry
+}
+
+// inferMCPTransport guesses the transport when "type" is omitted.
+// A command implies stdio; a URL implies sse when its path ends in /sse, http otherwise.
+func inferMCPTransport(e MCPServerEntry) string {
+	switch {
+	case e.Command != "":
+		return "stdio"
+	case e.URL != "":
+		if u, err := url.Parse(e.URL); err == nil && strings.HasSuffix(strings.TrimRight(u.Path, "/"), "/sse") {
+			return "sse"
+		}
+		return "http"
+	default:
+		return ""
+	}
+}
+
+// unmarshalField decodes obj[key] into dst, leaving dst untouched on a missing key or type mismatch.
+func unmarshalField(obj map[string]json.RawMessage, key string, dst any) {
+	if raw, ok := obj[key]; ok {
+		_ = json.Unmarshal(raw, dst)
+	}
+}
+
+// stringMap decodes a JSON object into a string map, formatting non-string values as JSON.
+func stringMap(raw json.RawMessage) map[string]string {
+	if raw == nil {
+		return nil
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil || len(obj) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(obj))
+	for k, v := range obj {
+		var str string
+		if err := json.Unmarshal(v, &str); err == nil {
+			out[k] = str
+		} else {
+			out[k] = compactJSON(v)
+		}
+	}
+	return out
+}
+
+// compactJSON returns raw JSON without insignificant whitespace.
+func compactJSON(raw json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return string(raw)
+	}
+	return buf.String()
 }
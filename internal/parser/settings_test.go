@@ -2,6 +2,7 @@ package parser
 
 import (
 	"sort"
+	"strings"
 	"testing"
 )
 
@@ -47,6 +48,74 @@ func TestParseSettingsHooks(t *testing.T) {
 	}
 }
 
+func TestParseSettingsHooks_MatcherGroups(t *testing.T) {
+	raw := `{
+		"hooks": {
+			"PreToolUse": [
+				{
+					"matcher": "Bash",
+					"hooks": [
+						{"type": "command", "command": "check-bash.sh", "timeout": 30},
+						{"type": "command", "command": "log.sh"}
+					]
+				},
+				{
+					"matcher": "Edit|Write",
+					"hooks": [{"type": "prompt", "prompt": "Review this edit"}]
+				}
+			]
+		}
+	}`
+
+	entries := ParseSettingsHooks(raw)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 hook event, got %d", len(entries))
+	}
+
+	e := entries[0]
+	if e.Count != 3 {
+		t.Errorf("expected count 3, got %d", e.Count)
+	}
+	if len(e.Commands) != 2 {
+		t.Errorf("expected 2 commands, got %v", e.Commands)
+	}
+	if len(e.Matchers) != 2 {
+		t.Fatalf("expected 2 matcher groups, got %d", len(e.Matchers))
+	}
+
+	bash := e.Matchers[0]
+	if bash.Matcher != "Bash" || len(bash.Hooks) != 2 {
+		t.Fatalf("unexpected first group: %+v", bash)
+	}
+	if bash.Hooks[0].Type != "command" || bash.Hooks[0].Command != "check-bash.sh" || bash.Hooks[0].Timeout != 30 {
+		t.Errorf("unexpected first hook: %+v", bash.Hooks[0])
+	}
+	if bash.Hooks[1].Timeout != 0 {
+		t.Errorf("expected no timeout, got %d", bash.Hooks[1].Timeout)
+	}
+
+	edit := e.Matchers[1]
+	if edit.Matcher != "Edit|Write" || edit.Hooks[0].Type != "prompt" || edit.Hooks[0].Prompt != "Review this edit" {
+		t.Errorf("unexpected second group: %+v", edit)
+	}
+}
+
+func TestParseSettingsHooks_LegacyFlatEntries(t *testing.T) {
+	raw := `{"hooks": {"Stop": [{"command": "cleanup.sh"}]}}`
+
+	entries := ParseSettingsHooks(raw)
+	if len(entries) != 1 || len(entries[0].Matchers) != 1 {
+		t.Fatalf("expected 1 event with 1 group, got %+v", entries)
+	}
+	m := entries[0].Matchers[0]
+	if m.Matcher != "" {
+		t.Errorf("expected empty matcher, got %q", m.Matcher)
+	}
+	if len(m.Hooks) != 1 || m.Hooks[0].Command != "cleanup.sh" || m.Hooks[0].Type != "command" {
+		t.Errorf("unexpected hooks: %+v", m.Hooks)
+	}
+}
+
 func TestParseSettingsHooks_NoHooks(t *testing.T) {
 	raw := `{"permissions": {}}`
 	entries := ParseSettingsHooks(raw)
@@ -106,3 +175,87 @@ func TestParseMCPServers_NoServers(t *testing.T) {
 		t.Errorf("expected nil for no mcpServers, got %v", entries)
 	}
 }
+
+func TestParseMCPServers_FullModel(t *testing.T) {
+	raw := `{
+		"mcpServers": {
+			"github": {
+				"command": "npx",
+				"args": ["-y", "@modelcontextprotocol/server-github"],
+				"env": {"GITHUB_TOKEN": "ghp_abc", "DEBUG": "1"},
+				"cwd": "/tmp"
+			},
+			"remote": {
+				"url": "https://example.com/mcp",
+				"headers": {"Authorization": "Bearer xyz"},
+				"timeout": 30
+			},
+			"events": {"url": "https://example.com/sse"},
+			"explicit": {"type": "http", "url": "https://example.com/sse"}
+		}
+	}`
+
+	servers := make(map[string]MCPServerEntry)
+	for _, e := range ParseMCPServers(raw) {
+		servers[e.Name] = e
+	}
+
+	gh := servers["github"]
+	if gh.Type != "stdio" || !gh.TypeInferred {
+		t.Errorf("github: type = %q (inferred=%v), want inferred stdio", gh.Type, gh.TypeInferred)
+	}
+	if len(gh.Args) != 2 || gh.Args[1] != "@modelcontextprotocol/server-github" {
+		t.Errorf("github: args = %v", gh.Args)
+	}
+	if gh.Env["GITHUB_TOKEN"] != "ghp_abc" || gh.Cwd != "/tmp" {
+		t.Errorf("github: env = %v, cwd = %q", gh.Env, gh.Cwd)
+	}
+
+	remote := servers["remote"]
+	if remote.Type != "http" || remote.Headers["Authorization"] != "Bearer xyz" {
+		t.Errorf("remote: type = %q, headers = %v", remote.Type, remote.Headers)
+	}
+	if remote.Extra["timeout"] != "30" {
+		t.Errorf("remote: extra = %v", remote.Extra)
+	}
+
+	if servers["events"].Type != "sse" {
+		t.Errorf("events: type = %q, want sse", servers["events"].Type)
+	}
+	if e := servers["explicit"]; e.Type != "http" || e.TypeInferred {
+		t.Errorf("explicit: type = %q (inferred=%v), want declared http", e.Type, e.TypeInferred)
+	}
+}
+
+func TestIsSecretKey(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"GITHUB_TOKEN", true},
+		{"OPENAI_API_KEY", true},
+		{"client_secret", true},
+		{"Authorization", true},
+		{"DB_PASSWORD", true},
+		{"DEBUG", false},
+		{"NODE_ENV", false},
+	}
+	for _, tt := range tests {
+		if got := IsSecretKey(tt.name); got != tt.want {
+			t.Errorf("IsSecretKey(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMaskSecret(t *testing.T) {
+	if got := MaskSecret("short"); strings.Contains(got, "short") {
+		t.Errorf("short value leaked: %q", got)
+	}
+	got := MaskSecret("ghp_1234567890abcdef")
+	if !strings.HasPrefix(got, "ghp_") || strings.Contains(got, "1234") {
+		t.Errorf("MaskSecret = %q", got)
+	}
+	if MaskSecret("") != "" {
+		t.Error("empty value should stay empty")
+	}
+}
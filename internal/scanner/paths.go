@@ -8,20 +8,18 @@ import (
 	"github.com/jeremy-kr/ccfg/internal/model"
 )
 
-// WatchPaths collects file and directory paths across all scopes for fsnotify watching.
-// For files, it adds both the parent directory (to detect creation/deletion) and the file
-// itself (to detect content changes, if it exists). Directories are added as-is.
-func WatchPaths(projectRoot string) []string {
-	seen := make(map[string]bool)
-	var paths []string
+// WatchPath is a path the file watcher should follow.
+type WatchPath struct {
+	Path      string // Absolute path; may not exist yet.
+	Recursive bool   // Whether every subdirectory should be watched too (category directories).
+}
 
-	add := func(p string) {
-		if seen[p] {
-			return
-		}
-		seen[p] = true
-		paths = append(paths, p)
-	}
+// WatchPaths collects file and directory paths across all scopes for file watching.
+// Paths are returned whether or not they exist, so the watcher can pick them up
+// once they are created. Category directories (commands/, skills/, agents/) are recursive.
+func WatchPaths(projectRoot string) []WatchPath {
+	seen := make(map[string]bool)
+	var paths []WatchPath
 
 	collect := func(base string, entries []FileEntry) {
 		if base == "" {
@@ -29,16 +27,11 @@ func WatchPaths(projectRoot string) []string {
 		}
 		for _, e := range entries {
 			abs := filepath.Join(base, e.RelPath)
-			if e.IsDir {
-				add(abs)
-			} else {
-				// Parent directory (to detect file creation/deletion)
-				add(filepath.Dir(abs))
-				// File itself (to detect content changes, only if it exists)
-				if _, err := os.Stat(abs); err == nil {
-					add(abs)
-				}
+			if seen[abs] {
+				continue
 			}
+			seen[abs] = true
+			paths = append(paths, WatchPath{Path: abs, Recursive: e.IsDir})
 		}
 	}
 
@@ -9,6 +9,7 @@ import (
 
 	"github.com/jeremy-kr/ccfg/internal/model"
 	"github.com/jeremy-kr/ccfg/internal/parser"
+	"github.com/jeremy-kr/ccfg/internal/schema"
 )
 
 // Scanner discovers Claude Code configuration files.
@@ -51,52 +52,150 @@ func (s *Scanner) Scan() (*model.ScanResult, error) {
 	if rootDir != "" {
 		if base, entries := ProjectPaths(rootDir); base != "" {
 			result.Project = scanEntries(base, entries, model.ScopeProject)
+			markGitTracked(rootDir, result.Project)
 		}
 	}
 
 	return result, nil
 }
 
-// scanEntries iterates over FileEntry items and collects metadata for each file.
-func scanEntries(base string, entries []FileEntry, scope model.Scope) []model.ConfigFile {
-	files := make([]model.ConfigFile, 0, len(entries))
-	for _, e := range entries {
-		absPath := filepath.Join(base, e.RelPath)
-		cf := model.ConfigFile{
-			Path:        absPath,
-			Scope:       scope,
-			FileType:    detectFileType(e.RelPath),
-			Category:    e.Category,
-			Description: e.Description,
+// Rescan refreshes prev after the given paths changed. Only entries affected by a
+// change (the entry itself, a file inside it, or a directory on its path) are
+// rescanned; all others are carried over from prev. It returns the new result and
+// the paths of the rescanned entries. A change of project root triggers a full scan.
+func (s *Scanner) Rescan(prev *model.ScanResult, changed []string) (*model.ScanResult, []string, error) {
+	workDir := s.WorkDir
+	if workDir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get working directory: %w", err)
 		}
+		workDir = wd
+	}
 
-		if info, err := os.Stat(absPath); err == nil {
-			cf.Exists = true
-			cf.Size = info.Size()
-			cf.ModTime = info.ModTime()
-			cf.IsDir = info.IsDir()
+	rootDir := FindProjectRoot(workDir)
+	if prev == nil || rootDir != prev.RootDir {
+		result, err := s.Scan()
+		if err != nil {
+			return nil, nil, err
+		}
+		var all []string
+		for _, f := range result.All() {
+			all = append(all, f.Path)
+		}
+		return result, all, nil
+	}
 
-			// Scan children if it is a directory
-			if e.IsDir && info.IsDir() {
-				cf.Children = scanDir(absPath, scope, e.Category)
-			}
+	result := &model.ScanResult{RootDir: rootDir}
+	var updated []string
 
-			// settings.json -> virtual children for hooks + mcpServers
-			if cf.Category == model.CategorySettings && !cf.IsDir {
-				cf.Children = parseSettingsSections(absPath, scope)
+	if base, entries := ManagedPaths(); base != "" {
+		result.Managed = rescanEntries(base, entries, model.ScopeManaged, prev.Managed, changed, &updated)
+	}
+	if base, entries := UserPaths(); base != "" {
+		result.User = rescanEntries(base, entries, model.ScopeUser, prev.User, changed, &updated)
+	}
+	if rootDir != "" {
+		if base, entries := ProjectPaths(rootDir); base != "" {
+			n := len(updated)
+			result.Project = rescanEntries(base, entries, model.ScopeProject, prev.Project, changed, &updated)
+			if len(updated) > n {
+				markGitTracked(rootDir, result.Project)
 			}
+		}
+	}
 
-			// .mcp.json -> virtual children for server list
-			if cf.Category == model.CategoryMCP && !cf.IsDir {
-				cf.Children = parseMCPSections(absPath, scope)
-			}
+	return result, updated, nil
+}
+
+// rescanEntries rescans the entries affected by changed and reuses prev for the rest.
+// Paths of rescanned entries are appended to updated.
+func rescanEntries(base string, entries []FileEntry, scope model.Scope, prev []model.ConfigFile, changed []string, updated *[]string) []model.ConfigFile {
+	old := make(map[string]model.ConfigFile, len(prev))
+	for _, f := range prev {
+		old[f.Path] = f
+	}
+
+	files := make([]model.ConfigFile, 0, len(entries))
+	for _, e := range entries {
+		absPath := filepath.Join(base, e.RelPath)
+		if f, ok := old[absPath]; ok && !affected(absPath, changed) {
+			files = append(files, f)
+			continue
 		}
+		files = append(files, scanEntry(base, e, scope))
+		*updated = append(*updated, absPath)
+	}
+	return files
+}
 
-		files = append(files, cf)
+// affected reports whether any changed path is path itself, inside it, or one of its ancestors.
+func affected(path string, changed []string) bool {
+	for _, c := range changed {
+		if c == path || isWithin(c, path) || isWithin(path, c) {
+			return true
+		}
+	}
+	return false
+}
+
+// isWithin reports whether path is strictly inside dir.
+func isWithin(path, dir string) bool {
+	return strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// scanEntries iterates over FileEntry items and collects metadata for each file.
+func scanEntries(base string, entries []FileEntry, scope model.Scope) []model.ConfigFile {
+	files := make([]model.ConfigFile, 0, len(entries))
+	for _, e := range entries {
+		files = append(files, scanEntry(base, e, scope))
 	}
 	return files
 }
 
+// scanEntry collects metadata for a single FileEntry.
+func scanEntry(base string, e FileEntry, scope model.Scope) model.ConfigFile {
+	absPath := filepath.Join(base, e.RelPath)
+	cf := model.ConfigFile{
+		Path:        absPath,
+		Scope:       scope,
+		FileType:    detectFileType(e.RelPath),
+		Category:    e.Category,
+		Description: e.Description,
+	}
+
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return cf
+	}
+	cf.Exists = true
+	cf.Size = info.Size()
+	cf.ModTime = info.ModTime()
+	cf.IsDir = info.IsDir()
+
+	// Scan children if it is a directory
+	if e.IsDir && info.IsDir() {
+		cf.Children = scanDir(absPath, scope, e.Category)
+	}
+
+	// settings.json -> virtual children for hooks + mcpServers
+	if cf.Category == model.CategorySettings && !cf.IsDir {
+		cf.Children = parseSettingsSections(absPath, scope)
+	}
+
+	// .mcp.json -> virtual children for server list
+	if cf.Category == model.CategoryMCP && !cf.IsDir {
+		cf.Children = parseMCPSections(absPath, scope)
+	}
+
+	if !cf.IsDir {
+		// Validate against the bundled JSON Schema, if one applies
+		cf.Diagnostics = validateFile(absPath, cf.Category)
+		cf.Secrets = detectSecrets(absPath, cf.Size)
+	}
+	return cf
+}
+
 // scanDir scans files within a directory.
 // It also follows symbolic links that point to directories.
 func scanDir(dir string, scope model.Scope, category model.ConfigCategory) []model.ConfigFile {
@@ -133,6 +232,8 @@ func scanDir(dir string, scope model.Scope, category model.ConfigCategory) []mod
 		// Recursively scan subdirectories
 		if info.IsDir() {
 			cf.Children = scanDir(absPath, scope, category)
+		} else {
+			cf.Secrets = detectSecrets(absPath, cf.Size)
 		}
 
 		children = append(children, cf)
@@ -158,14 +259,17 @@ func parseSettingsSections(path string, scope model.Scope) []model.ConfigFile {
 		})
 		var hookChildren []model.ConfigFile
 		for _, h := range hooks {
+			eventPath := path + "#hooks." + h.Event
 			hookChildren = append(hookChildren, model.ConfigFile{
-				Path:        path + "#hooks." + h.Event,
+				Path:        eventPath,
 				Scope:       scope,
 				FileType:    model.FileTypeJSON,
 				Category:    model.CategoryHooks,
 				Exists:      true,
+				IsDir:       len(h.Matchers) > 0,
 				IsVirtual:   true,
 				Description: h.Event,
+				Children:    buildHookMatcherNodes(eventPath, scope, h.Matchers),
 			})
 		}
 		children = append(children, model.ConfigFile{
@@ -189,6 +293,60 @@ func parseSettingsSections(path string, scope model.Scope) []model.ConfigFile {
 	return children
 }
 
+// buildHookMatcherNodes creates matcher group nodes (and their command nodes) for a hook event.
+// Nodes are addressed by index since matcher patterns may contain dots.
+func buildHookMatcherNodes(eventPath string, scope model.Scope, matchers []parser.HookMatcher) []model.ConfigFile {
+	nodes := make([]model.ConfigFile, 0, len(matchers))
+	for i, m := range matchers {
+		matcherPath := fmt.Sprintf("%s.%d", eventPath, i)
+		label := m.Matcher
+		if label == "" {
+			label = "*"
+		}
+
+		var cmdNodes []model.ConfigFile
+		for j, h := range m.Hooks {
+			cmdNodes = append(cmdNodes, model.ConfigFile{
+				Path:        fmt.Sprintf("%s.%d", matcherPath, j),
+				Scope:       scope,
+				FileType:    model.FileTypeJSON,
+				Category:    model.CategoryHooks,
+				Exists:      true,
+				IsVirtual:   true,
+				Description: hookLabel(h),
+			})
+		}
+
+		nodes = append(nodes, model.ConfigFile{
+			Path:        matcherPath,
+			Scope:       scope,
+			FileType:    model.FileTypeJSON,
+			Category:    model.CategoryHooks,
+			Exists:      true,
+			IsDir:       true,
+			IsVirtual:   true,
+			Description: label,
+			Children:    cmdNodes,
+		})
+	}
+	return nodes
+}
+
+// hookLabel returns a single-line tree label for a hook handler.
+func hookLabel(h parser.HookCommand) string {
+	label := h.Command
+	if label == "" {
+		label = h.Prompt
+	}
+	if label == "" && h.Type != "" {
+		label = "(" + h.Type + ")"
+	}
+	if label == "" {
+		label = "(empty hook)"
+	}
+	return strings.Join(strings.Fields(label), " ")
+}
+
 // parseMCPSections parses the server list from .mcp.json and creates virtual children.
 func parseMCPSections(path string, scope model.Scope) []model.ConfigFile {
 	data, err := os.ReadFile(path)
@@ -241,6 +399,20 @@ func buildMCPServerGroup(path string, scope model.Scope, raw string) *model.Conf
 	}
 }
 
+// validateFile validates a config file against its bundled schema.
+// It returns nil when no schema applies or the file cannot be read.
+func validateFile(path string, category model.ConfigCategory) []model.Diagnostic {
+	s := schema.For(path, category)
+	if s == nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return schema.Validate(s, data)
+}
+
 // detectFileType determines the FileType based on the file path extension.
 func detectFileType(path string) model.FileType {
 	switch strings.ToLower(filepath.Ext(path)) {
@@ -82,6 +82,116 @@ func TestScanWithoutProject(t *testing.T) {
 	}
 }
 
+func TestParseSettingsSections_HookTree(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	raw := `{"hooks": {"PreToolUse": [{"matcher": "Bash", "hooks": [{"type": "command", "command": "check.sh"}]}]}}`
+	if err := os.WriteFile(path, []byte(raw), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	children := parseSettingsSections(path, model.ScopeUser)
+	if len(children) != 1 || len(children[0].Children) != 1 {
+		t.Fatalf("expected hooks group with 1 event, got %+v", children)
+	}
+
+	event := children[0].Children[0]
+	if event.Description != "PreToolUse" || len(event.Children) != 1 {
+		t.Fatalf("unexpected event node: %+v", event)
+	}
+
+	matcher := event.Children[0]
+	if matcher.Description != "Bash" || matcher.Path != path+"#hooks.PreToolUse.0" {
+		t.Errorf("unexpected matcher node: %s (%s)", matcher.Description, matcher.Path)
+	}
+	if len(matcher.Children) != 1 || matcher.Children[0].Description != "check.sh" {
+		t.Fatalf("unexpected command nodes: %+v", matcher.Children)
+	}
+	if matcher.Children[0].Path != path+"#hooks.PreToolUse.0.0" {
+		t.Errorf("command path = %s", matcher.Children[0].Path)
+	}
+}
+
+func TestScanAttachesDiagnostics(t *testing.T) {
+	projectDir := filepath.Join(t.TempDir(), "project")
+	if err := os.MkdirAll(filepath.Join(projectDir, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(projectDir, ".claude"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	settings := `{"permissions": {"defaultMode": "yolo"}}`
+	if err := os.WriteFile(filepath.Join(projectDir, ".claude", "settings.json"), []byte(settings), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := New(projectDir).Scan()
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+
+	for _, cf := range result.Project {
+		if cf.Path != filepath.Join(projectDir, ".claude", "settings.json") {
+			continue
+		}
+		if errs, _ := cf.DiagnosticCounts(); errs != 1 {
+			t.Errorf("expected 1 error diagnostic, got %+v", cf.Diagnostics)
+		}
+		return
+	}
+	t.Error("settings.json not found in Project scope")
+}
+
+func TestRescanUpdatesOnlyAffectedEntries(t *testing.T) {
+	projectDir := filepath.Join(t.TempDir(), "project")
+	skills := filepath.Join(projectDir, ".claude", "skills")
+	if err := os.MkdirAll(filepath.Join(projectDir, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(skills, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	claudeMD := filepath.Join(projectDir, "CLAUDE.md")
+	if err := os.WriteFile(claudeMD, []byte("# Test"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := New(projectDir)
+	prev, err := s.Scan()
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+
+	// Add a nested skill and rescan with only that path.
+	skillFile := filepath.Join(skills, "review", "SKILL.md")
+	if err := os.MkdirAll(filepath.Dir(skillFile), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(skillFile, []byte("# review"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, updated, err := s.Rescan(prev, []string{skillFile})
+	if err != nil {
+		t.Fatalf("Rescan() error: %v", err)
+	}
+	if len(updated) != 1 || updated[0] != skills {
+		t.Fatalf("updated = %v, want [%s]", updated, skills)
+	}
+
+	for _, cf := range result.Project {
+		switch cf.Path {
+		case skills:
+			if len(cf.Children) != 1 || len(cf.Children[0].Children) != 1 {
+				t.Errorf("skills children not rescanned: %+v", cf.Children)
+			}
+		case claudeMD:
+			if !cf.Exists {
+				t.Error("CLAUDE.md should be carried over from the previous scan")
+			}
+		}
+	}
+}
+
 func TestDetectFileType(t *testing.T) {
 	tests := []struct {
 		path string
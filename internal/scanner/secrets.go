@@ -0,0 +1,76 @@
+package scanner
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/jeremy-kr/ccfg/internal/model"
+	"github.com/jeremy-kr/ccfg/internal/secrets"
+)
+
+// maxSecretScanSize skips files too large to be hand-written config.
+const maxSecretScanSize = 1 << 20
+
+// detectSecrets scans a regular file for likely credentials.
+func detectSecrets(path string, size int64) []model.SecretFinding {
+	if size > maxSecretScanSize {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return secrets.Scan(string(data))
+}
+
+// markGitTracked sets GitTracked on project files that contain secrets and are tracked by git.
+// Only files with findings are checked, so git is not invoked for clean projects.
+func markGitTracked(root string, files []model.ConfigFile) {
+	var paths []string
+	collectSecretPaths(files, &paths)
+	if len(paths) == 0 {
+		return
+	}
+
+	tracked := gitTrackedFiles(root, paths)
+	applyGitTracked(files, tracked)
+}
+
+func collectSecretPaths(files []model.ConfigFile, out *[]string) {
+	for _, f := range files {
+		if len(f.Secrets) > 0 {
+			*out = append(*out, f.Path)
+		}
+		collectSecretPaths(f.Children, out)
+	}
+}
+
+func applyGitTracked(files []model.ConfigFile, tracked map[string]bool) {
+	for i := range files {
+		if tracked[files[i].Path] {
+			files[i].GitTracked = true
+		}
+		applyGitTracked(files[i].Children, tracked)
+	}
+}
+
+// gitTrackedFiles returns the subset of paths tracked in the git repository at root.
+// It returns nil if git is unavailable or fails.
+func gitTrackedFiles(root string, paths []string) map[string]bool {
+	args := append([]string{"-C", root, "ls-files", "-z", "--full-name", "--"}, paths...)
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil
+	}
+
+	tracked := make(map[string]bool)
+	for _, rel := range bytes.Split(out, []byte{0}) {
+		if len(rel) == 0 {
+			continue
+		}
+		tracked[filepath.Join(root, filepath.FromSlash(string(rel)))] = true
+	}
+	return tracked
+}
@@ -0,0 +1,131 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// node is a decoded JSON value annotated with its source line.
+type node struct {
+	kind    string           // "object", "array", "string", "number", "boolean" or "null".
+	value   any              // Scalar value (nil for objects and arrays).
+	line    int              // 1-based line where the value starts.
+	keyLine int              // 1-based line of the member key (0 if not an object member).
+	keys    []string         // Object member keys in document order.
+	members map[string]*node // Object members by key.
+	items   []*node          // Array elements.
+}
+
+// document decodes JSON while tracking source positions.
+type document struct {
+	dec        *json.Decoder
+	src        []byte
+	lineStarts []int
+}
+
+// parseDocument decodes src into a node tree. src must already have comments
+// blanked out (parser.BlankJSONC) so offsets match the original file.
+func parseDocument(src []byte) (*node, error) {
+	d := &document{
+		dec: json.NewDecoder(bytes.NewReader(src)),
+		src: src,
+	}
+	d.dec.UseNumber()
+	d.lineStarts = append(d.lineStarts, 0)
+	for i, b := range src {
+		if b == '\n' {
+			d.lineStarts = append(d.lineStarts, i+1)
+		}
+	}
+	root, err := d.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := d.dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after top-level value on line %d", d.nextLine())
+	}
+	return root, nil
+}
+
+// lineAt returns the 1-based line containing the byte offset.
+func (d *document) lineAt(offset int) int {
+	return sort.Search(len(d.lineStarts), func(i int) bool {
+		return d.lineStarts[i] > offset
+	})
+}
+
+// nextLine returns the line of the next token, skipping whitespace and separators.
+func (d *document) nextLine() int {
+	off := int(d.dec.InputOffset())
+	for off < len(d.src) {
+		switch d.src[off] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			off++
+			continue
+		}
+		break
+	}
+	return d.lineAt(off)
+}
+
+func (d *document) parseValue() (*node, error) {
+	line := d.nextLine()
+	tok, err := d.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	n := &node{line: line}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			n.kind = "object"
+			n.members = make(map[string]*node)
+			for d.dec.More() {
+				keyLine := d.nextLine()
+				keyTok, err := d.dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, _ := keyTok.(string)
+				child, err := d.parseValue()
+				if err != nil {
+					return nil, err
+				}
+				child.keyLine = keyLine
+				if _, dup := n.members[key]; !dup {
+					n.keys = append(n.keys, key)
+				}
+				n.members[key] = child
+			}
+		case '[':
+			n.kind = "array"
+			for d.dec.More() {
+				child, err := d.parseValue()
+				if err != nil {
+					return nil, err
+				}
+				n.items = append(n.items, child)
+			}
+		default:
+			return nil, fmt.Errorf("unexpected %q", t)
+		}
+		// Consume the closing delimiter.
+		if _, err := d.dec.Token(); err != nil {
+			return nil, err
+		}
+	case string:
+		n.kind, n.value = "string", t
+	case json.Number:
+		n.kind, n.value = "number", t
+	case bool:
+		n.kind, n.value = "boolean", t
+	case nil:
+		n.kind = "null"
+	}
+	return n, nil
+}
@@ -0,0 +1,272 @@
+// Package schema validates Claude Code config files against bundled JSON Schemas.
+//
+// Only the subset of JSON Schema used by the bundled schemas is supported:
+// type, properties, additionalProperties, items, enum, $ref (local $defs) and
+// the deprecated/deprecationMessage annotations.
+package schema
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/jeremy-kr/ccfg/internal/model"
+	"github.com/jeremy-kr/ccfg/internal/parser"
+)
+
+//go:embed *.schema.json
+var schemaFS embed.FS
+
+// Schema is a JSON Schema node.
+type Schema struct {
+	Type                 typeList           `json:"type"`
+	Properties           map[string]*Schema `json:"properties"`
+	AdditionalProperties *additional        `json:"additionalProperties"`
+	Items                *Schema            `json:"items"`
+	Enum                 []any              `json:"enum"`
+	Ref                  string             `json:"$ref"`
+	Defs                 map[string]*Schema `json:"$defs"`
+	Deprecated           bool               `json:"deprecated"`
+	DeprecationMessage   string             `json:"deprecationMessage"`
+}
+
+// typeList accepts "type" as either a string or an array of strings.
+type typeList []string
+
+func (t *typeList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = typeList{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*t = many
+	return nil
+}
+
+// additional accepts "additionalProperties" as either a boolean or a schema.
+type additional struct {
+	allowed bool
+	schema  *Schema
+}
+
+func (a *additional) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &a.allowed); err == nil {
+		return nil
+	}
+	a.allowed = true
+	return json.Unmarshal(data, &a.schema)
+}
+
+var (
+	settingsSchema = mustLoad("settings.schema.json")
+	mcpSchema      = mustLoad("mcp.schema.json")
+)
+
+func mustLoad(name string) *Schema {
+	data, err := schemaFS.ReadFile(name)
+	if err != nil {
+		panic(fmt.Sprintf("schema: missing bundled schema %s: %v", name, err))
+	}
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		panic(fmt.Sprintf("schema: invalid bundled schema %s: %v", name, err))
+	}
+	return &s
+}
+
+// For returns the bundled schema for a config file, or nil if none applies.
+// Only settings files and .mcp.json are covered; the legacy ~/.claude.json holds
+// unrelated application state and is not validated.
+func For(path string, category model.ConfigCategory) *Schema {
+	base := filepath.Base(path)
+	switch {
+	case category == model.CategorySettings &&
+		(base == "settings.json" || base == "settings.local.json" ||
+			base == "managed_settings.json" || base == "managed-settings.json"):
+		return settingsSchema
+	case category == model.CategoryMCP && base == ".mcp.json":
+		return mcpSchema
+	default:
+		return nil
+	}
+}
+
+// Validate checks raw JSONC content against s and returns the findings in document order.
+// A syntax error yields a single error diagnostic.
+func Validate(s *Schema, raw []byte) []model.Diagnostic {
+	src := []byte(parser.BlankJSONC(string(raw)))
+	root, err := parseDocument(src)
+	if err != nil {
+		return []model.Diagnostic{syntaxDiagnostic(src, err)}
+	}
+
+	v := &validator{root: s}
+	v.validate(root, s, "")
+	return v.diags
+}
+
+// syntaxDiagnostic converts a decode error into a diagnostic with a line number when available.
+func syntaxDiagnostic(src []byte, err error) model.Diagnostic {
+	d := model.Diagnostic{Severity: model.SeverityError, Message: "invalid JSON: " + err.Error()}
+	if errors.Is(err, io.EOF) {
+		d.Message = "invalid JSON: unexpected end of file"
+		return d
+	}
+	var syn *json.SyntaxError
+	if errors.As(err, &syn) {
+		d.Line = 1 + strings.Count(string(src[:min(int(syn.Offset), len(src))]), "\n")
+	}
+	return d
+}
+
+type validator struct {
+	root  *Schema
+	diags []model.Diagnostic
+}
+
+func (v *validator) add(sev model.Severity, path string, line int, format string, args ...any) {
+	v.diags = append(v.diags, model.Diagnostic{
+		Severity: sev,
+		Path:     path,
+		Line:     line,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// resolve follows a local "#/$defs/<name>" reference.
+func (v *validator) resolve(s *Schema) *Schema {
+	for s != nil && s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, "#/$defs/")
+		s = v.root.Defs[name]
+	}
+	return s
+}
+
+func (v *validator) validate(n *node, s *Schema, path string) {
+	s = v.resolve(s)
+	if s == nil {
+		return
+	}
+
+	if s.Deprecated {
+		line := n.keyLine
+		if line == 0 {
+			line = n.line
+		}
+		msg := "deprecated key"
+		if s.DeprecationMessage != "" {
+			msg += ": " + s.DeprecationMessage
+		}
+		v.add(model.SeverityWarning, path, line, "%s", msg)
+	}
+
+	if len(s.Type) > 0 && !matchesType(n, s.Type) {
+		v.add(model.SeverityError, path, n.line, "wrong type: expected %s, got %s", strings.Join(s.Type, " or "), n.kind)
+		return
+	}
+
+	if len(s.Enum) > 0 && !inEnum(n, s.Enum) {
+		v.add(model.SeverityError, path, n.line, "invalid value %s (expected one of: %s)", formatValue(n), formatEnum(s.Enum))
+	}
+
+	switch n.kind {
+	case "object":
+		for _, key := range n.keys {
+			child := n.members[key]
+			childPath := joinPath(path, key)
+			if prop, ok := s.Properties[key]; ok {
+				v.validate(child, prop, childPath)
+				continue
+			}
+			if s.AdditionalProperties == nil {
+				continue
+			}
+			if s.AdditionalProperties.schema != nil {
+				v.validate(child, s.AdditionalProperties.schema, childPath)
+			} else if !s.AdditionalProperties.allowed {
+				v.add(model.SeverityWarning, childPath, child.keyLine, "unknown key %q", key)
+			}
+		}
+	case "array":
+		if s.Items != nil {
+			for i, item := range n.items {
+				v.validate(item, s.Items, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	}
+}
+
+// matchesType reports whether n has one of the given JSON Schema types.
+func matchesType(n *node, types []string) bool {
+	for _, t := range types {
+		switch t {
+		case n.kind:
+			return true
+		case "integer":
+			if num, ok := n.value.(json.Number); ok && !strings.ContainsAny(num.String(), ".eE") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inEnum reports whether the scalar value of n equals one of the enum values.
+func inEnum(n *node, enum []any) bool {
+	for _, e := range enum {
+		switch ev := e.(type) {
+		case string:
+			if s, ok := n.value.(string); ok && s == ev {
+				return true
+			}
+		case bool:
+			if b, ok := n.value.(bool); ok && b == ev {
+				return true
+			}
+		case float64:
+			if num, ok := n.value.(json.Number); ok {
+				if f, err := num.Float64(); err == nil && f == ev {
+					return true
+				}
+			}
+		case nil:
+			if n.kind == "null" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func formatValue(n *node) string {
+	if s, ok := n.value.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	if n.value == nil {
+		return n.kind
+	}
+	return fmt.Sprintf("%v", n.value)
+}
+
+func formatEnum(enum []any) string {
+	parts := make([]string, 0, len(enum))
+	for _, e := range enum {
+		parts = append(parts, fmt.Sprintf("%v", e))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,113 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jeremy-kr/ccfg/internal/model"
+)
+
+func TestValidate_Settings(t *testing.T) {
+	raw := `{
+  // Comments must not affect line numbers
+  "model": "opus",
+  "cleanupPeriodDays": "thirty",
+  "permissions": {
+    "allow": ["Bash(go test:*)", 42],
+    "defaultMode": "yolo"
+  },
+  "includeCoAuthoredBy": false,
+  "someFutureKey": true,
+}`
+
+	diags := Validate(For("/home/me/.claude/settings.json", model.CategorySettings), []byte(raw))
+
+	want := []struct {
+		path     string
+		line     int
+		severity model.Severity
+		contains string
+	}{
+		{"cleanupPeriodDays", 4, model.SeverityError, "expected integer"},
+		{"permissions.allow[1]", 6, model.SeverityError, "expected string"},
+		{"permissions.defaultMode", 7, model.SeverityError, `invalid value "yolo"`},
+		{"includeCoAuthoredBy", 9, model.SeverityWarning, "deprecated"},
+		{"someFutureKey", 10, model.SeverityWarning, "unknown key"},
+	}
+	if len(diags) != len(want) {
+		t.Fatalf("expected %d diagnostics, got %d: %+v", len(want), len(diags), diags)
+	}
+	for i, w := range want {
+		d := diags[i]
+		if d.Path != w.path || d.Line != w.line || d.Severity != w.severity || !strings.Contains(d.Message, w.contains) {
+			t.Errorf("diag %d = %+v, want path=%s line=%d severity=%s message~%q", i, d, w.path, w.line, w.severity, w.contains)
+		}
+	}
+}
+
+func TestValidate_ValidSettings(t *testing.T) {
+	raw := `{
+		"permissions": {"allow": ["Read"], "defaultMode": "plan"},
+		"hooks": {"PreToolUse": [{"matcher": "Bash", "hooks": [{"type": "command", "command": "x", "timeout": 5}]}]},
+		"env": {"FOO": "bar"},
+		"showTurnDuration": false
+	}`
+	if diags := Validate(settingsSchema, []byte(raw)); len(diags) != 0 {
+		t.Errorf("expected no diagnostics, got %+v", diags)
+	}
+}
+
+func TestValidate_NestedKeysAreOpen(t *testing.T) {
+	// Only top-level keys are checked; nested settings added upstream must not warn.
+	raw := `{
+		"permissions": {"allow": ["Read"], "newRuleKind": []},
+		"hooks": {"NewEvent": [], "PreToolUse": [{"matcher": "Bash", "hooks": [{"type": "command", "command": "x", "async": true}]}]},
+		"statusLine": {"type": "command", "command": "x", "refresh": 5}
+	}`
+	if diags := Validate(settingsSchema, []byte(raw)); len(diags) != 0 {
+		t.Errorf("expected no diagnostics, got %+v", diags)
+	}
+}
+
+func TestValidate_MCP(t *testing.T) {
+	raw := `{
+  "mcpServers": {
+    "a": {"type": "websocket", "command": "x"},
+    "b": {"command": "y", "env": {"PORT": 8080}, "bogus": 1}
+  }
+}`
+	diags := Validate(For("/p/.mcp.json", model.CategoryMCP), []byte(raw))
+
+	paths := make([]string, 0, len(diags))
+	for _, d := range diags {
+		paths = append(paths, d.Path)
+	}
+	got := strings.Join(paths, ",")
+	// Unknown server keys such as "bogus" are accepted; only the top level is closed.
+	if got != "mcpServers.a.type,mcpServers.b.env.PORT" {
+		t.Errorf("unexpected diagnostic paths: %s", got)
+	}
+}
+
+func TestValidate_SyntaxError(t *testing.T) {
+	raw := "{\n  \"model\": \"opus\"\n  \"env\": {}\n}"
+	diags := Validate(settingsSchema, []byte(raw))
+	if len(diags) != 1 || diags[0].Severity != model.SeverityError {
+		t.Fatalf("expected a single error, got %+v", diags)
+	}
+	if diags[0].Line != 3 {
+		t.Errorf("syntax error line = %d, want 3", diags[0].Line)
+	}
+}
+
+func TestFor(t *testing.T) {
+	if For("/home/me/.claude.json", model.CategorySettings) != nil {
+		t.Error("legacy ~/.claude.json should not be validated")
+	}
+	if For("/home/me/.claude/settings.local.json", model.CategorySettings) != settingsSchema {
+		t.Error("settings.local.json should use the settings schema")
+	}
+	if For("/home/me/.claude/keybindings.json", model.CategoryKeybindings) != nil {
+		t.Error("keybindings.json has no bundled schema")
+	}
+}
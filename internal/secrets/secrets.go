@@ -0,0 +1,123 @@
+// Package secrets detects credentials accidentally pasted into config files.
+package secrets
+
+import (
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/jeremy-kr/ccfg/internal/model"
+	"github.com/jeremy-kr/ccfg/internal/parser"
+)
+
+// rule is a known token format.
+type rule struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// rules lists known token formats. More specific prefixes come first so that
+// e.g. an Anthropic key is not also reported as an OpenAI key.
+var rules = []rule{
+	{"private key", regexp.MustCompile(`-----BEGIN (?:[A-Z]+ )*PRIVATE KEY-----`)},
+	{"Anthropic API key", regexp.MustCompile(`\bsk-ant-[A-Za-z0-9_-]{20,}`)},
+	{"OpenAI API key", regexp.MustCompile(`\bsk-(?:proj-|svcacct-)?[A-Za-z0-9_-]{20,}`)},
+	{"GitHub token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})`)},
+	{"GitLab token", regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}`)},
+	{"AWS access key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{"Stripe key", regexp.MustCompile(`\b[rs]k_(?:live|test)_[0-9A-Za-z]{16,}`)},
+	{"npm token", regexp.MustCompile(`\bnpm_[A-Za-z0-9]{36}\b`)},
+	{"JWT", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+}
+
+// assignmentPattern matches "KEY": "value", KEY=value and KEY: value pairs.
+var assignmentPattern = regexp.MustCompile(`"?([A-Za-z_][A-Za-z0-9_.-]*)"?\s*[:=]\s*"?([^"\s,}]+)`)
+
+// tokenPattern matches long opaque tokens that may be unlabelled secrets.
+var tokenPattern = regexp.MustCompile(`[A-Za-z0-9+/_=-]{32,}`)
+
+const (
+	// minAssignedLen and minAssignedEntropy apply to values of secret-looking keys.
+	minAssignedLen     = 16
+	minAssignedEntropy = 3.5
+	// minTokenEntropy applies to unlabelled tokens; hex digests stay below it (max 4 bits/char).
+	minTokenEntropy = 4.3
+)
+
+// Scan returns the likely credentials in content, at most one finding per line.
+func Scan(content string) []model.SecretFinding {
+	var findings []model.SecretFinding
+	for i, line := range strings.Split(content, "\n") {
+		if f, ok := scanLine(line); ok {
+			f.Line = i + 1
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// scanLine checks known token formats first, then the entropy heuristics.
+func scanLine(line string) (model.SecretFinding, bool) {
+	for _, r := range rules {
+		if m := r.pattern.FindString(line); m != "" {
+			return model.SecretFinding{Rule: r.name, Masked: parser.MaskSecret(m)}, true
+		}
+	}
+
+	for _, m := range assignmentPattern.FindAllStringSubmatch(line, -1) {
+		key, value := m[1], strings.Trim(m[2], `'"`)
+		if !parser.IsSecretKey(key) || isPlaceholder(value) {
+			continue
+		}
+		value = strings.TrimPrefix(value, "Bearer ")
+		if len(value) >= minAssignedLen && entropy(value) >= minAssignedEntropy {
+			return model.SecretFinding{Rule: "high-entropy " + key, Masked: parser.MaskSecret(value)}, true
+		}
+	}
+
+	for _, m := range tokenPattern.FindAllString(line, -1) {
+		if isPlaceholder(m) || !hasLettersAndDigits(m) || strings.Count(m, "/") > 2 {
+			continue
+		}
+		if entropy(m) >= minTokenEntropy {
+			return model.SecretFinding{Rule: "high-entropy string", Masked: parser.MaskSecret(m)}, true
+		}
+	}
+	return model.SecretFinding{}, false
+}
+
+// isPlaceholder reports whether a value is an env reference, path or template rather than a literal secret.
+func isPlaceholder(v string) bool {
+	lower := strings.ToLower(v)
+	return strings.Contains(v, "${") || strings.HasPrefix(v, "$") ||
+		strings.HasPrefix(v, "/") || strings.HasPrefix(v, "~") || strings.HasPrefix(v, ".") ||
+		strings.Contains(lower, "your") || strings.Contains(lower, "xxxx") ||
+		strings.Contains(lower, "example") || strings.Contains(lower, "placeholder")
+}
+
+func hasLettersAndDigits(s string) bool {
+	return strings.ContainsAny(s, "0123456789") && strings.IndexFunc(s, func(r rune) bool {
+		return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+	}) >= 0
+}
+
+// entropy returns the Shannon entropy of s in bits per character.
+func entropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	freq := make(map[rune]int)
+	n := 0
+	for _, r := range s {
+		freq[r]++
+		n++
+	}
+	var h float64
+	for _, c := range freq {
+		p := float64(c) / float64(n)
+		h -= p * math.Log2(p)
+	}
+	return h
+}
@@ -0,0 +1,134 @@
+package timeline
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around each change.
+const contextLines = 3
+
+// maxDiffLines bounds the line-based LCS (quadratic in the number of lines).
+const maxDiffLines = 5000
+
+// lineOp is a single line of an edit script.
+type lineOp struct {
+	kind byte // ' ' (equal), '-' (removed), '+' (added)
+	text string
+	a, b int // 0-based line index in the old / new text
+}
+
+// Unified returns a unified diff between two texts, labelled with name.
+// Returns an empty string when the texts are equal.
+func Unified(name, before, after string) string {
+	if before == after {
+		return ""
+	}
+	a, b := splitLines(before), splitLines(after)
+	if len(a) > maxDiffLines || len(b) > maxDiffLines {
+		return fmt.Sprintf("--- a/%s\n+++ b/%s\n(file too large to diff: %d → %d lines)\n", name, name, len(a), len(b))
+	}
+
+	ops := editScript(a, b)
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- a/%s\n+++ b/%s\n", name, name)
+	for _, h := range hunks(ops) {
+		writeHunk(&out, ops[h[0]:h[1]])
+	}
+	return out.String()
+}
+
+// splitLines splits text into lines without their trailing newline.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// editScript computes a minimal line edit script from a to b using an LCS table.
+func editScript(a, b []string) []lineOp {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]lineOp, 0, n+m)
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && a[i] == b[j]:
+			ops = append(ops, lineOp{' ', a[i], i, j})
+			i++
+			j++
+		case i < n && (j == m || lcs[i+1][j] >= lcs[i][j+1]):
+			// Prefer removals first so replaced lines read as -old +new.
+			ops = append(ops, lineOp{'-', a[i], i, j})
+			i++
+		default:
+			ops = append(ops, lineOp{'+', b[j], i, j})
+			j++
+		}
+	}
+	return ops
+}
+
+// hunks groups changed ops with surrounding context into [start, end) ranges,
+// merging ranges whose context overlaps.
+func hunks(ops []lineOp) [][2]int {
+	var out [][2]int
+	for i, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+		start := max(i-contextLines, 0)
+		end := min(i+contextLines+1, len(ops))
+		if n := len(out); n > 0 && start <= out[n-1][1] {
+			out[n-1][1] = max(out[n-1][1], end)
+			continue
+		}
+		out = append(out, [2]int{start, end})
+	}
+	return out
+}
+
+// writeHunk writes a single @@ hunk.
+func writeHunk(out *strings.Builder, ops []lineOp) {
+	var aLen, bLen int
+	for _, op := range ops {
+		if op.kind != '+' {
+			aLen++
+		}
+		if op.kind != '-' {
+			bLen++
+		}
+	}
+	fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(ops[0].a, aLen), hunkRange(ops[0].b, bLen))
+	for _, op := range ops {
+		out.WriteByte(op.kind)
+		out.WriteString(op.text)
+		out.WriteByte('\n')
+	}
+}
+
+// hunkRange formats a 1-based hunk range; empty ranges point at the preceding line.
+func hunkRange(start, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if length == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
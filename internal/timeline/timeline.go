@@ -0,0 +1,189 @@
+// Package timeline records config file edits made while ccfg is running.
+package timeline
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jeremy-kr/ccfg/internal/merger"
+	"github.com/jeremy-kr/ccfg/internal/model"
+)
+
+// maxSnapshotSize skips files too large to be hand-written config.
+const maxSnapshotSize = 1 << 20
+
+// maxEntries bounds the in-memory history; the oldest entries are dropped first.
+const maxEntries = 500
+
+// Kind describes what happened to a file in an Entry.
+type Kind int
+
+const (
+	KindModified Kind = iota // Content changed
+	KindCreated              // File appeared
+	KindRemoved              // File disappeared
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindCreated:
+		return "created"
+	case KindRemoved:
+		return "removed"
+	default:
+		return "modified"
+	}
+}
+
+// Entry is a single recorded file edit.
+type Entry struct {
+	Time     time.Time
+	Path     string
+	Kind     Kind
+	Diff     string               // Unified diff against the previous snapshot
+	Settings []merger.ValueChange // Effective settings changed by the same batch of edits
+}
+
+// Timeline keeps the last known content of every tracked file and the edits seen since.
+type Timeline struct {
+	snapshots map[string]string // Last known content per path (absent = missing)
+	entries   []Entry           // Oldest first
+}
+
+// New creates an empty Timeline.
+func New() *Timeline {
+	return &Timeline{snapshots: make(map[string]string)}
+}
+
+// Seed snapshots every existing file in result as the baseline for later diffs.
+func (t *Timeline) Seed(result *model.ScanResult) {
+	for _, f := range result.All() {
+		t.seedFile(f)
+	}
+}
+
+func (t *Timeline) seedFile(f model.ConfigFile) {
+	if f.IsVirtual || !f.Exists {
+		return
+	}
+	if f.IsDir {
+		for _, c := range f.Children {
+			t.seedFile(c)
+		}
+		return
+	}
+	if content, ok := readSnapshot(f.Path); ok {
+		t.snapshots[f.Path] = content
+	}
+}
+
+// Record snapshots the given paths and appends an entry for each file whose
+// content differs from its previous snapshot. A directory path covers every file
+// inside it, so created or removed folders are recorded file by file. Oversized
+// files are skipped. settings is attached to every entry of the batch.
+// Returns the new entries.
+func (t *Timeline) Record(at time.Time, paths []string, settings []merger.ValueChange) []Entry {
+	var added []Entry
+	for _, p := range t.expand(paths) {
+		if info, err := os.Stat(p); err == nil && info.Size() > maxSnapshotSize {
+			continue
+		}
+
+		before, existed := t.snapshots[p]
+		after, exists := readSnapshot(p)
+
+		var kind Kind
+		switch {
+		case !existed && !exists:
+			continue
+		case !existed:
+			kind = KindCreated
+		case !exists:
+			kind = KindRemoved
+		case before == after:
+			continue
+		}
+
+		if exists {
+			t.snapshots[p] = after
+		} else {
+			delete(t.snapshots, p)
+		}
+
+		added = append(added, Entry{
+			Time:     at,
+			Path:     p,
+			Kind:     kind,
+			Diff:     Unified(filepath.Base(p), before, after),
+			Settings: settings,
+		})
+	}
+
+	t.entries = append(t.entries, added...)
+	if over := len(t.entries) - maxEntries; over > 0 {
+		t.entries = append([]Entry(nil), t.entries[over:]...)
+	}
+	return added
+}
+
+// expand replaces directory paths with the files inside them, both on disk and
+// in the snapshots (for removed directories). The result is sorted and deduplicated.
+func (t *Timeline) expand(paths []string) []string {
+	set := make(map[string]bool)
+	for _, p := range paths {
+		prefix := p + string(filepath.Separator)
+		for snap := range t.snapshots {
+			if strings.HasPrefix(snap, prefix) {
+				set[snap] = true
+			}
+		}
+
+		info, err := os.Stat(p)
+		if err != nil || !info.IsDir() {
+			set[p] = true
+			continue
+		}
+		_ = filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() {
+				set[path] = true
+			}
+			return nil
+		})
+	}
+
+	out := make([]string, 0, len(set))
+	for p := range set {
+		out = append(out, p)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Entries returns the recorded edits, newest first.
+func (t *Timeline) Entries() []Entry {
+	out := make([]Entry, len(t.entries))
+	for i, e := range t.entries {
+		out[len(t.entries)-1-i] = e
+	}
+	return out
+}
+
+// Len returns the number of recorded edits.
+func (t *Timeline) Len() int { return len(t.entries) }
+
+// readSnapshot reads a file for snapshotting. ok is false if it is missing or too large.
+func readSnapshot(path string) (string, bool) {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() || info.Size() > maxSnapshotSize {
+		return "", false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
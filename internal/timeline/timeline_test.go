@@ -0,0 +1,111 @@
+package timeline
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremy-kr/ccfg/internal/model"
+)
+
+func TestUnified(t *testing.T) {
+	before := "a\nb\nc\nd\ne\nf\ng\nh\n"
+	after := "a\nb\nc\nD\ne\nf\ng\nh\ni\n"
+
+	want := `--- a/x.txt
++++ b/x.txt
+@@ -1,8 +1,9 @@
+ a
+ b
+ c
+-d
++D
+ e
+ f
+ g
+ h
++i
+`
+	if got := Unified("x.txt", before, after); got != want {
+		t.Errorf("Unified() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestUnified_SeparateHunks(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 20; i++ {
+		a = append(a, string(rune('a'+i)))
+		b = append(b, string(rune('a'+i)))
+	}
+	b[1] = "X"
+	b[18] = "Y"
+
+	got := Unified("f", strings.Join(a, "\n"), strings.Join(b, "\n"))
+	if n := strings.Count(got, "@@ -"); n != 2 {
+		t.Fatalf("expected 2 hunks, got %d:\n%s", n, got)
+	}
+	if !strings.Contains(got, "@@ -1,5 +1,5 @@") || !strings.Contains(got, "@@ -16,5 +16,5 @@") {
+		t.Errorf("unexpected hunk headers:\n%s", got)
+	}
+}
+
+func TestUnified_Equal(t *testing.T) {
+	if got := Unified("f", "same\n", "same\n"); got != "" {
+		t.Errorf("expected empty diff, got %q", got)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	dir := t.TempDir()
+	settings := filepath.Join(dir, "settings.json")
+	if err := os.WriteFile(settings, []byte("{\n  \"model\": \"opus\"\n}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tl := New()
+	tl.Seed(&model.ScanResult{User: []model.ConfigFile{{Path: settings, Exists: true}}})
+
+	// Unchanged content records nothing.
+	if got := tl.Record(time.Now(), []string{settings}, nil); len(got) != 0 {
+		t.Fatalf("expected no entries, got %+v", got)
+	}
+
+	if err := os.WriteFile(settings, []byte("{\n  \"model\": \"sonnet\"\n}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got := tl.Record(time.Now(), []string{settings}, nil)
+	if len(got) != 1 || got[0].Kind != KindModified {
+		t.Fatalf("expected one modified entry, got %+v", got)
+	}
+	if !strings.Contains(got[0].Diff, `-  "model": "opus"`) || !strings.Contains(got[0].Diff, `+  "model": "sonnet"`) {
+		t.Errorf("unexpected diff:\n%s", got[0].Diff)
+	}
+
+	// A new folder is recorded file by file; removing it records removals.
+	skill := filepath.Join(dir, "skills", "review")
+	if err := os.MkdirAll(skill, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(skill, "SKILL.md"), []byte("# review\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got = tl.Record(time.Now(), []string{skill}, nil)
+	if len(got) != 1 || got[0].Kind != KindCreated || filepath.Base(got[0].Path) != "SKILL.md" {
+		t.Fatalf("expected created SKILL.md, got %+v", got)
+	}
+
+	if err := os.RemoveAll(skill); err != nil {
+		t.Fatal(err)
+	}
+	got = tl.Record(time.Now(), []string{skill}, nil)
+	if len(got) != 1 || got[0].Kind != KindRemoved {
+		t.Fatalf("expected removed SKILL.md, got %+v", got)
+	}
+
+	entries := tl.Entries()
+	if len(entries) != 3 || entries[0].Kind != KindRemoved {
+		t.Errorf("Entries() should list 3 edits newest first, got %+v", entries)
+	}
+}
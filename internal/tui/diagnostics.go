@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jeremy-kr/ccfg/internal/model"
+)
+
+var (
+	diagErrorStyle   = lipgloss.NewStyle().Bold(true).Foreground(colorRed)
+	diagWarningStyle = lipgloss.NewStyle().Bold(true).Foreground(colorOrange)
+)
+
+// fileBadges returns the diagnostic and secret badges shown after a tree node label.
+func fileBadges(f *model.ConfigFile) string {
+	return diagnosticBadge(f) + secretBadge(f)
+}
+
+// diagnosticBadge returns a compact error/warning count for a tree node, or "" if clean.
+func diagnosticBadge(f *model.ConfigFile) string {
+	errs, warns := f.DiagnosticCounts()
+	var parts []string
+	if errs > 0 {
+		parts = append(parts, diagErrorStyle.Render(fmt.Sprintf("✖%d", errs)))
+	}
+	if warns > 0 {
+		parts = append(parts, diagWarningStyle.Render(fmt.Sprintf("⚠%d", warns)))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " " + strings.Join(parts, " ")
+}
+
+// renderDiagnostics renders schema findings as a block shown above the file content.
+// Line numbers refer to the file on disk, not the pretty-printed preview.
+func renderDiagnostics(diags []model.Diagnostic) string {
+	if len(diags) == 0 {
+		return ""
+	}
+
+	rows := make([][]string, 0, len(diags))
+	for _, d := range diags {
+		icon := diagWarningStyle.Render("⚠")
+		if d.Severity == model.SeverityError {
+			icon = diagErrorStyle.Render("✖")
+		}
+		line := "-"
+		if d.Line > 0 {
+			line = fmt.Sprintf("L%d", d.Line)
+		}
+		path := d.Path
+		if path == "" {
+			path = "(file)"
+		}
+		rows = append(rows, []string{icon, line, path, d.Message})
+	}
+
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(colorYellow).Render(
+		fmt.Sprintf("🩺 Schema diagnostics (%d)", len(diags))))
+	b.WriteString("\n")
+	b.WriteString(renderTable([]string{"", "LINE", "KEY", "PROBLEM"}, rows))
+	b.WriteString(tableRuleStyle.Render(strings.Repeat("━", 40)))
+	b.WriteString("\n")
+	return b.String()
+}
@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jeremy-kr/ccfg/internal/parser"
+)
+
+// renderHooksSection renders the hooks section addressed by dotPath as a table.
+// dotPath is "hooks", "hooks.<event>", "hooks.<event>.<group>" or "hooks.<event>.<group>.<hook>".
+func renderHooksSection(raw, dotPath string) string {
+	parts := strings.Split(dotPath, ".")
+	if len(parts) == 0 || parts[0] != "hooks" {
+		return fmt.Sprintf("(section not found: %s)", dotPath)
+	}
+
+	entries := parser.ParseSettingsHooks(raw)
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Event < entries[j].Event
+	})
+
+	event := ""
+	groupIdx, hookIdx := -1, -1
+	if len(parts) > 1 {
+		event = parts[1]
+	}
+	if len(parts) > 2 {
+		groupIdx, _ = strconv.Atoi(parts[2])
+	}
+	if len(parts) > 3 {
+		hookIdx, _ = strconv.Atoi(parts[3])
+	}
+
+	var rows [][]string
+	var detail *parser.HookCommand
+	for _, e := range entries {
+		if event != "" && e.Event != event {
+			continue
+		}
+		for gi, m := range e.Matchers {
+			if groupIdx >= 0 && gi != groupIdx {
+				continue
+			}
+			matcher := m.Matcher
+			if matcher == "" {
+				matcher = "*"
+			}
+			for hi, h := range m.Hooks {
+				if hookIdx >= 0 && hi != hookIdx {
+					continue
+				}
+				if hookIdx >= 0 {
+					h := h
+					detail = &h
+				}
+				rows = append(rows, []string{e.Event, matcher, h.Type, formatTimeout(h.Timeout), hookBody(h)})
+			}
+		}
+	}
+
+	if len(rows) == 0 {
+		return fmt.Sprintf("(section not found: %s)", dotPath)
+	}
+
+	var b strings.Builder
+	title := lipgloss.NewStyle().Bold(true).Foreground(colorYellow)
+	b.WriteString(title.Render(fmt.Sprintf("🪝 Hooks (%d)", len(rows))))
+	b.WriteString("\n\n")
+	b.WriteString(renderTable([]string{"EVENT", "MATCHER", "TYPE", "TIMEOUT", "COMMAND"}, rows))
+
+	// A single hook also shows its full command, since the table row may be cut off.
+	if detail != nil {
+		b.WriteString("\n")
+		b.WriteString(tableHeaderStyle.Render(strings.ToUpper(detail.Type)))
+		b.WriteString("\n")
+		body := detail.Command
+		if body == "" {
+			body = detail.Prompt
+		}
+		b.WriteString(body)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// hookBody returns the command or prompt of a hook collapsed onto a single line.
+func hookBody(h parser.HookCommand) string {
+	body := h.Command
+	if body == "" {
+		body = h.Prompt
+	}
+	return strings.Join(strings.Fields(body), " ")
+}
+
+// formatTimeout formats a hook timeout in seconds, or "-" when unset.
+func formatTimeout(seconds int) string {
+	if seconds <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%ds", seconds)
+}
@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+const hooksRaw = `{
+	"hooks": {
+		"PreToolUse": [
+			{"matcher": "Bash", "hooks": [{"type": "command", "command": "check-bash.sh", "timeout": 30}]},
+			{"matcher": "Edit", "hooks": [{"type": "command", "command": "fmt.sh"}]}
+		],
+		"Stop": [{"hooks": [{"type": "command", "command": "notify.sh"}]}]
+	}
+}`
+
+func TestRenderHooksSection_Event(t *testing.T) {
+	out := renderHooksSection(hooksRaw, "hooks.PreToolUse")
+
+	for _, want := range []string{"MATCHER", "Bash", "check-bash.sh", "30s", "Edit", "fmt.sh"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "notify.sh") {
+		t.Errorf("event filter leaked other events:\n%s", out)
+	}
+}
+
+func TestRenderHooksSection_Matcher(t *testing.T) {
+	out := renderHooksSection(hooksRaw, "hooks.PreToolUse.1")
+	if !strings.Contains(out, "fmt.sh") || strings.Contains(out, "check-bash.sh") {
+		t.Errorf("unexpected matcher output:\n%s", out)
+	}
+
+	all := renderHooksSection(hooksRaw, "hooks")
+	if !strings.Contains(all, "notify.sh") || !strings.Contains(all, "*") {
+		t.Errorf("expected all events with catch-all matcher:\n%s", all)
+	}
+}
+
+func TestRenderHooksSection_NotFound(t *testing.T) {
+	out := renderHooksSection(hooksRaw, "hooks.SessionStart")
+	if !strings.Contains(out, "section not found") {
+		t.Errorf("expected not-found message, got:\n%s", out)
+	}
+}
@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jeremy-kr/ccfg/internal/parser"
+)
+
+var (
+	kbDuplicateStyle = lipgloss.NewStyle().Foreground(colorRed)
+	kbUnknownStyle   = lipgloss.NewStyle().Foreground(colorMagenta)
+	kbShadowStyle    = lipgloss.NewStyle().Foreground(colorOrange)
+	kbUnboundStyle   = lipgloss.NewStyle().Foreground(colorDimGray)
+)
+
+// renderKeybindings renders keybindings.json as one table per context.
+// It returns an empty string when the file has no parseable bindings.
+func renderKeybindings(raw string) string {
+	bindings := parser.ParseKeybindings(raw)
+	if len(bindings) == 0 {
+		return ""
+	}
+
+	// Group by context, preserving first-appearance order.
+	var contexts []string
+	groups := make(map[string][]parser.Keybinding)
+	issues := 0
+	for _, kb := range bindings {
+		if _, ok := groups[kb.Context]; !ok {
+			contexts = append(contexts, kb.Context)
+		}
+		groups[kb.Context] = append(groups[kb.Context], kb)
+		if kb.Duplicate || kb.UnknownAction || kb.Shadows != "" {
+			issues++
+		}
+	}
+
+	var b strings.Builder
+	title := lipgloss.NewStyle().Bold(true).Foreground(colorYellow)
+	b.WriteString(title.Render(fmt.Sprintf("🎮 Keybindings (%d)", len(bindings))))
+	if issues > 0 {
+		b.WriteString("  " + kbDuplicateStyle.Render(fmt.Sprintf("⚠ %d issue(s)", issues)))
+	}
+	b.WriteString("\n")
+
+	for _, ctx := range contexts {
+		label := ctx
+		if label == "" {
+			label = "(no context)"
+		}
+		b.WriteString("\n")
+		b.WriteString(dirStyle.Render(fmt.Sprintf("▸ %s (%d)", label, len(groups[ctx]))))
+		b.WriteString("\n")
+
+		rows := make([][]string, 0, len(groups[ctx]))
+		for _, kb := range groups[ctx] {
+			rows = append(rows, keybindingRow(kb))
+		}
+		b.WriteString(renderTable([]string{"CHORD", "ACTION", "NOTES"}, rows))
+	}
+	return b.String()
+}
+
+// keybindingRow renders a binding as a table row with its issues highlighted.
+func keybindingRow(kb parser.Keybinding) []string {
+	chord := kb.Chord
+	action := kb.Action
+	if kb.Unbound {
+		action = kbUnboundStyle.Render("(unbound)")
+	}
+
+	var notes []string
+	if kb.Duplicate {
+		chord = kbDuplicateStyle.Render(chord)
+		notes = append(notes, kbDuplicateStyle.Render("duplicate chord"))
+	}
+	if kb.UnknownAction {
+		action = kbUnknownStyle.Render(action)
+		notes = append(notes, kbUnknownStyle.Render("unknown action"))
+	}
+	if kb.Shadows != "" {
+		if !kb.Duplicate {
+			chord = kbShadowStyle.Render(chord)
+		}
+		notes = append(notes, kbShadowStyle.Render("shadows terminal "+kb.Shadows))
+	}
+	return []string{chord, action, strings.Join(notes, ", ")}
+}
@@ -0,0 +1,29 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderKeybindings_GroupsByContext(t *testing.T) {
+	raw := `{"bindings": [
+		{"context": "Chat", "bindings": {"ctrl+e": "chat:externalEditor", "ctrl+z": "chat:undo"}},
+		{"context": "Global", "bindings": {"ctrl+t": "app:nope"}}
+	]}`
+
+	out := renderKeybindings(raw)
+	for _, want := range []string{"Chat (2)", "Global (1)", "chat:externalEditor", "unknown action", "shadows terminal"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "Chat") > strings.Index(out, "Global") {
+		t.Error("contexts should keep file order")
+	}
+}
+
+func TestRenderKeybindings_Empty(t *testing.T) {
+	if out := renderKeybindings(`{}`); out != "" {
+		t.Errorf("expected empty output for no bindings, got %q", out)
+	}
+}
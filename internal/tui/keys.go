@@ -20,6 +20,8 @@ type keyMap struct {
 	Merge    key.Binding
 	Ranking  key.Binding
 	Period   key.Binding
+	Reveal   key.Binding
+	Timeline key.Binding
 	Quit     key.Binding
 }
 
@@ -72,6 +74,14 @@ var keys = keyMap{
 		key.WithKeys("p"),
 		key.WithHelp("p", "period"),
 	),
+	Reveal: key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "reveal secrets"),
+	),
+	Timeline: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "change timeline"),
+	),
 	Quit: key.NewBinding(
 		key.WithKeys("q", "ctrl+c"),
 		key.WithHelp("q", "quit"),
@@ -79,7 +89,7 @@ var keys = keyMap{
 }
 
 // renderHUD renders the HUD footer.
-func renderHUD(existCount, totalCount int, scopeName string, scanSec float64, watching bool) string {
+func renderHUD(existCount, totalCount int, scopeName string, scanSec float64, watchMode, notice string) string {
 	sep := hudSep.Render(" │ ")
 
 	nav := hudLabelNav.Render("[NAV]") + " " +
@@ -91,6 +101,7 @@ func renderHUD(existCount, totalCount int, scopeName string, scanSec float64, wa
 		hudKey.Render("/") + hudDesc.Render(" search  ") +
 		hudKey.Render("m") + hudDesc.Render(" merge  ") +
 		hudKey.Render("r") + hudDesc.Render(" ranking  ") +
+		hudKey.Render("t") + hudDesc.Render(" timeline  ") +
 		hudKey.Render("q") + hudDesc.Render(" quit")
 
 	stats := fmt.Sprintf("📊 %s/%s",
@@ -102,8 +113,11 @@ func renderHUD(existCount, totalCount int, scopeName string, scanSec float64, wa
 	scan := hudDesc.Render(fmt.Sprintf("⏱ %.1fs", scanSec))
 
 	hud := nav + sep + cmd + sep + stats + sep + scope + sep + scan
-	if watching {
-		hud += sep + lipgloss.NewStyle().Foreground(colorGreen).Render("👁 watching")
+	if watchMode != "" {
+		hud += sep + lipgloss.NewStyle().Foreground(colorGreen).Render("👁 "+watchMode)
+	}
+	if notice != "" {
+		hud += sep + changedStyle.Render("✎ "+notice)
 	}
 	return hud
 }
@@ -0,0 +1,156 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jeremy-kr/ccfg/internal/model"
+	"github.com/jeremy-kr/ccfg/internal/parser"
+	"github.com/jeremy-kr/ccfg/internal/usage"
+)
+
+// renderMCPSection renders the MCP section addressed by dotPath.
+// "mcpServers" renders a server table; "mcpServers.<name>" renders a single server summary.
+// Secret env/header values are masked unless reveal is true.
+func renderMCPSection(raw, dotPath string, reveal bool) string {
+	servers := parser.ParseMCPServers(raw)
+	sort.Slice(servers, func(i, j int) bool {
+		return servers[i].Name < servers[j].Name
+	})
+
+	if dotPath == "mcpServers" {
+		if len(servers) == 0 {
+			return "(no MCP servers)"
+		}
+		return renderMCPTable(servers)
+	}
+
+	name := strings.TrimPrefix(dotPath, "mcpServers.")
+	for _, s := range servers {
+		if s.Name == name {
+			return renderMCPServer(s, reveal)
+		}
+	}
+	return fmt.Sprintf("(section not found: %s)", dotPath)
+}
+
+// renderMCPTable renders all servers as a NAME/TYPE/TARGET table.
+func renderMCPTable(servers []parser.MCPServerEntry) string {
+	rows := make([][]string, 0, len(servers))
+	for _, s := range servers {
+		rows = append(rows, []string{s.Name, transportLabel(s), mcpTarget(s)})
+	}
+
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(colorYellow).Render(fmt.Sprintf("🔧 MCP Servers (%d)", len(servers))))
+	b.WriteString("\n\n")
+	b.WriteString(renderTable([]string{"NAME", "TYPE", "TARGET"}, rows))
+	return b.String()
+}
+
+// renderMCPServer renders a structured summary of a single server.
+func renderMCPServer(s parser.MCPServerEntry, reveal bool) string {
+	var b strings.Builder
+	title := lipgloss.NewStyle().Bold(true).Foreground(colorYellow).Render("🔧 " + s.Name)
+	b.WriteString(title + "  " + lipgloss.NewStyle().Foreground(colorMagenta).Render("["+transportLabel(s)+"]"))
+	b.WriteString("\n")
+	b.WriteString(tableRuleStyle.Render(strings.Repeat("━", 40)))
+	b.WriteString("\n\n")
+
+	field := func(label, value string) {
+		if value == "" {
+			return
+		}
+		b.WriteString(tableHeaderStyle.Render(fmt.Sprintf("%-9s", label)))
+		b.WriteString(" " + value + "\n")
+	}
+	field("Command", s.Command)
+	field("Args", strings.Join(s.Args, " "))
+	field("Cwd", s.Cwd)
+	field("URL", s.URL)
+
+	masked := 0
+	masked += writeKeyValues(&b, "Env", s.Env, reveal)
+	masked += writeKeyValues(&b, "Headers", s.Headers, reveal)
+	masked += writeKeyValues(&b, "Other", s.Extra, reveal)
+
+	if masked > 0 {
+		b.WriteString("\n")
+		b.WriteString(hudDesc.Render(fmt.Sprintf("🔒 %d secret value(s) masked — press v to reveal", masked)))
+		b.WriteString("\n")
+	} else if reveal && hasSecretKeys(s) {
+		b.WriteString("\n")
+		b.WriteString(lipgloss.NewStyle().Foreground(colorRed).Render("🔓 secrets revealed — press v to mask"))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// writeKeyValues writes a sorted key/value block and returns the number of masked values.
+func writeKeyValues(b *strings.Builder, label string, kv map[string]string, reveal bool) int {
+	if len(kv) == 0 {
+		return 0
+	}
+
+	keys := make([]string, 0, len(kv))
+	width := 0
+	for k := range kv {
+		keys = append(keys, k)
+		width = max(width, lipgloss.Width(k))
+	}
+	sort.Strings(keys)
+
+	b.WriteString("\n")
+	b.WriteString(tableHeaderStyle.Render(label))
+	b.WriteString("\n")
+
+	masked := 0
+	for _, k := range keys {
+		v := kv[k]
+		if !reveal && parser.IsSecretKey(k) {
+			v = fileMissingStyle.Render(parser.MaskSecret(v))
+			masked++
+		}
+		b.WriteString(fmt.Sprintf("  %s%s  %s\n", k, strings.Repeat(" ", width-lipgloss.Width(k)), v))
+	}
+	return masked
+}
+
+// hasSecretKeys reports whether any env, header or other key of s looks like a credential.
+func hasSecretKeys(s parser.MCPServerEntry) bool {
+	for _, kv := range []map[string]string{s.Env, s.Headers, s.Extra} {
+		for k := range kv {
+			if parser.IsSecretKey(k) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// transportLabel returns the transport type, marking inferred types with a trailing "?".
+func transportLabel(s parser.MCPServerEntry) string {
+	switch {
+	case s.Type == "":
+		return "unknown"
+	case s.TypeInferred:
+		return s.Type + "?"
+	default:
+		return s.Type
+	}
+}
+
+// mcpTarget returns the command line or URL a server connects to.
+func mcpTarget(s parser.MCPServerEntry) string {
+	if s.URL != "" {
+		return s.URL
+	}
+	return strings.TrimSpace(s.Command + " " + strings.Join(s.Args, " "))
+}
+
+// configuredMCPServers returns the names of all MCP servers defined in the scanned files.
+func configuredMCPServers(result *model.ScanResult) []string {
+	return itemNames(configItems(result), usage.RankMCP)
+}
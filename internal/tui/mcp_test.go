@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+const mcpRaw = `{
+	"mcpServers": {
+		"github": {
+			"command": "npx",
+			"args": ["-y", "server-github"],
+			"env": {"GITHUB_TOKEN": "ghp_1234567890abcdef", "DEBUG": "1"}
+		},
+		"remote": {
+			"type": "http",
+			"url": "https://example.com/mcp",
+			"apiKey": "sk-remote-0987654321",
+			"timeout": 30
+		}
+	}
+}`
+
+func TestRenderMCPSection_MasksSecrets(t *testing.T) {
+	out := renderMCPSection(mcpRaw, "mcpServers.github", false)
+	if strings.Contains(out, "1234567890abcdef") {
+		t.Errorf("secret leaked in masked output:\n%s", out)
+	}
+	for _, want := range []string{"stdio?", "npx", "-y server-github", "DEBUG", "press v to reveal"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	revealed := renderMCPSection(mcpRaw, "mcpServers.github", true)
+	if !strings.Contains(revealed, "ghp_1234567890abcdef") {
+		t.Errorf("secret not revealed:\n%s", revealed)
+	}
+}
+
+func TestRenderMCPSection_MasksOtherKeys(t *testing.T) {
+	out := renderMCPSection(mcpRaw, "mcpServers.remote", false)
+	if strings.Contains(out, "0987654321") {
+		t.Errorf("secret in other keys leaked in masked output:\n%s", out)
+	}
+	for _, want := range []string{"timeout", "30", "1 secret value(s) masked"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	revealed := renderMCPSection(mcpRaw, "mcpServers.remote", true)
+	if !strings.Contains(revealed, "sk-remote-0987654321") || !strings.Contains(revealed, "press v to mask") {
+		t.Errorf("secret in other keys not revealed:\n%s", revealed)
+	}
+}
+
+func TestRenderMCPSection_Table(t *testing.T) {
+	out := renderMCPSection(mcpRaw, "mcpServers", false)
+	if !strings.Contains(out, "NAME") || !strings.Contains(out, "npx -y server-github") {
+		t.Errorf("unexpected table:\n%s", out)
+	}
+}
@@ -3,6 +3,7 @@ package tui
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/jeremy-kr/ccfg/internal/merger"
 	"github.com/jeremy-kr/ccfg/internal/model"
 	"github.com/jeremy-kr/ccfg/internal/scanner"
+	"github.com/jeremy-kr/ccfg/internal/timeline"
 	"github.com/jeremy-kr/ccfg/internal/usage"
 	"github.com/jeremy-kr/ccfg/internal/watcher"
 )
@@ -41,31 +43,73 @@ type Model struct {
 	merged       *merger.MergedConfig
 	rankingMode  bool
 	ranking      RankingModel
+	rankingLoop  int // Incremented when the ranking view opens or closes; stale polls stop.
+	trophyMode   bool
+	trophies     TrophyModel
+	timelineMode bool
+	timelineView TimelineModel
+	timeline     *timeline.Timeline // Edits recorded while running.
 	scanDuration time.Duration
 	watcher      *watcher.Watcher  // File watcher (nil if inactive).
 	sc           *scanner.Scanner  // For rescanning.
+	notice       string            // Change notification shown in the HUD (empty when none).
+	noticeSeq    int               // Incremented per notification; stale clear messages are ignored.
+}
+
+// noticeDuration is how long a change notification and its tree highlight stay visible.
+const noticeDuration = 3 * time.Second
+
+// clearNoticeMsg clears the change notification with the matching sequence number.
+type clearNoticeMsg struct{ seq int }
+
+// Options configures optional TUI behavior from command-line flags.
+type Options struct {
+	Watch watcher.Options // File watcher backend and polling interval.
+	Range usage.DateRange // Initial date range of the ranking view (zero uses the period).
 }
 
 // NewModel creates a TUI model from a ScanResult.
-func NewModel(result *model.ScanResult, scanDuration time.Duration, s *scanner.Scanner) Model {
+func NewModel(result *model.ScanResult, scanDuration time.Duration, s *scanner.Scanner, opts Options) Model {
 	tree := NewTreeModel(result)
 	homeDir, _ := os.UserHomeDir()
+	cacheDir := ""
+	if dir, err := os.UserCacheDir(); err == nil {
+		cacheDir = filepath.Join(dir, "ccfg")
+	}
+	prices, priceErr := usage.DefaultPrices, error(nil)
+	if dir, err := os.UserConfigDir(); err == nil {
+		prices, priceErr = usage.LoadPrices(filepath.Join(dir, "ccfg", "prices.json"))
+	}
+	grading, gradeErr := usage.Grading{}, error(nil)
+	if dir, err := os.UserConfigDir(); err == nil {
+		grading, gradeErr = usage.LoadGrading(filepath.Join(dir, "ccfg", "grading.json"))
+	}
+	tl := timeline.New()
+	tl.Seed(result)
 	m := Model{
 		scan:         result,
 		tree:         tree,
 		focus:        PaneTree,
 		merged:       merger.Merge(result),
-		ranking:      NewRankingModel(&usage.Collector{HomeDir: homeDir, ProjectPath: result.RootDir}),
+		ranking:      NewRankingModel(&usage.Collector{HomeDir: homeDir, ProjectPath: result.RootDir, CacheDir: cacheDir, Prices: prices, Grading: grading, MCPServers: configuredMCPServers(result), Commands: customCommandNames(result), Config: configItems(result)}),
+		timelineView: NewTimelineModel(tl),
+		timeline:     tl,
 		scanDuration: scanDuration,
 		sc:           s,
 	}
+	m.ranking.priceErr = priceErr
+	m.ranking.gradeErr = gradeErr
+	m.ranking.dateRange = opts.Range
 	if f := tree.SelectedFile(); f != nil {
 		m.preview.SetFile(f)
 	}
 
 	// Create file watcher (nil on failure — operates without watching).
-	paths := scanner.WatchPaths(result.RootDir)
-	if w, err := watcher.New(paths); err == nil {
+	var targets []watcher.Target
+	for _, p := range scanner.WatchPaths(result.RootDir) {
+		targets = append(targets, watcher.Target{Path: p.Path, Recursive: p.Recursive})
+	}
+	if w, err := watcher.New(targets, opts.Watch); err == nil {
 		m.watcher = w
 	}
 
@@ -100,7 +144,34 @@ func (m Model) Init() tea.Cmd {
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case watcher.FileChangedMsg:
-		return m.handleFileChanged()
+		return m.handleFileChanged(msg)
+
+	case rankingLoadedMsg:
+		if !m.rankingMode || !m.ranking.Loaded(msg) {
+			return m, nil
+		}
+		if m.trophyMode {
+			m.trophies.SetAchievements(m.ranking.Achievements())
+		}
+		m.rankingLoop++
+		return m, m.ranking.PollCmd(m.rankingLoop)
+
+	case rankingTailMsg:
+		if msg.loop != m.rankingLoop || !m.rankingMode {
+			return m, nil
+		}
+		m.ranking.ApplyTail(msg)
+		if m.trophyMode {
+			m.trophies.SetAchievements(m.ranking.Achievements())
+		}
+		return m, m.ranking.PollCmd(m.rankingLoop)
+
+	case clearNoticeMsg:
+		if msg.seq == m.noticeSeq {
+			m.notice = ""
+			m.tree.ClearHighlight()
+		}
+		return m, nil
 
 	case watcher.ErrorMsg:
 		return m, m.waitCmd()
@@ -123,6 +194,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateRanking(msg)
 		}
 
+		// Timeline mode.
+		if m.timelineMode {
+			return m.updateTimeline(msg)
+		}
+
 		switch {
 		case key.Matches(msg, keys.Quit):
 			if m.watcher != nil {
@@ -142,8 +218,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, keys.Ranking):
 			m.rankingMode = true
 			m.mergeMode = false
-			m.ranking.Load()
 			m.ranking.SetHeight(m.contentHeight() - rankingHeaderRows)
+			m.rankingLoop++
+			return m, m.ranking.Load()
+
+		case key.Matches(msg, keys.Timeline):
+			m.timelineMode = true
+			m.mergeMode = false
+			m.timelineView.Load()
+			m.timelineView.SetHeight(m.contentHeight())
+			return m, nil
+
+		case key.Matches(msg, keys.Reveal):
+			m.preview.ToggleSecrets()
+			m.syncPreview()
 			return m, nil
 
 		case key.Matches(msg, keys.Tab):
@@ -236,6 +324,11 @@ func (m Model) View() string {
 		return m.renderRankingView()
 	}
 
+	// Timeline mode — fullscreen.
+	if m.timelineMode {
+		return m.renderTimelineView()
+	}
+
 	// Header — decorated line.
 	header := m.renderHeader()
 
@@ -250,7 +343,7 @@ func (m Model) View() string {
 		existCount, totalCount := m.fileStats()
 		scopeName := m.tree.SelectedScope().String()
 		scanSec := m.scanDuration.Seconds()
-		footer = footerStyle.Render(renderHUD(existCount, totalCount, scopeName, scanSec, m.watcher != nil))
+		footer = footerStyle.Render(renderHUD(existCount, totalCount, scopeName, scanSec, m.watchLabel(), m.notice))
 	}
 
 	// Main area dimensions.
@@ -277,8 +370,12 @@ func (m Model) View() string {
 
 func (m *Model) renderHeader() string {
 	subtitle := "Claude Code Config Viewer ⚡"
-	if m.rankingMode {
+	if m.trophyMode {
+		subtitle = lipgloss.NewStyle().Bold(true).Foreground(colorYellow).Render("🏅 TROPHY CASE 🏅")
+	} else if m.rankingMode {
 		subtitle = lipgloss.NewStyle().Bold(true).Foreground(colorYellow).Render("🏆 RANKING VIEW 🏆")
+	} else if m.timelineMode {
+		subtitle = lipgloss.NewStyle().Bold(true).Foreground(colorCyan).Render("🕘 CHANGE TIMELINE 🕘")
 	} else if m.mergeMode {
 		subtitle = lipgloss.NewStyle().Bold(true).Foreground(colorMagenta).Render("⚡ MERGE VIEW ⚡")
 	}
@@ -320,11 +417,30 @@ func (m *Model) renderMergeView(width, height int) string {
 }
 
 func (m Model) updateRanking(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.ranking.EditingRange() {
+		cmd := m.ranking.UpdateRangePrompt(msg)
+		if cmd != nil {
+			// Stop polling the old range; polling restarts once the new one is loaded.
+			m.rankingLoop++
+		}
+		return m, cmd
+	}
+	if m.trophyMode {
+		return m.updateTrophies(msg)
+	}
 	switch {
 	case key.Matches(msg, keys.Quit):
+		m.ranking.Cancel()
 		return m, tea.Quit
+	case msg.Type == tea.KeyEscape && m.ranking.Back():
+		return m, nil
+	case key.Matches(msg, keys.Toggle):
+		m.ranking.Drill()
+		return m, nil
 	case msg.Type == tea.KeyEscape, key.Matches(msg, keys.Ranking):
 		m.rankingMode = false
+		m.rankingLoop++
+		m.ranking.Cancel()
 		return m, nil
 	case key.Matches(msg, keys.Up):
 		m.ranking.MoveUp()
@@ -343,24 +459,75 @@ func (m Model) updateRanking(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.ranking.SetTab(usage.RankTools)
 		case "3":
 			m.ranking.SetTab(usage.RankSkills)
+		case "4":
+			m.ranking.SetTab(usage.RankTokens)
+		case "5":
+			m.ranking.SetTab(usage.RankMCP)
+		case "6":
+			m.ranking.SetTab(usage.RankCommands)
+		case "7":
+			m.ranking.SetTab(usage.RankSessions)
+		case "8":
+			m.ranking.SetTab(usage.RankUnused)
+		case "9":
+			m.ranking.SetTab(usage.RankErrors)
+		case "0":
+			m.ranking.SetTab(usage.RankHotspots)
+		case "g":
+			m.ranking.CycleGroup()
+		case "G":
+			m.ranking.CycleGrading()
 		case "s":
-			m.ranking.ToggleScope()
+			m.rankingLoop++
+			return m, m.ranking.ToggleScope()
 		case "p":
-			m.ranking.TogglePeriod()
+			m.rankingLoop++
+			return m, m.ranking.TogglePeriod()
+		case "d":
+			m.ranking.OpenRangePrompt()
+		case "a":
+			m.trophyMode = true
+			m.trophies.SetHeight(m.contentHeight() - trophyHeaderRows)
+			m.trophies.SetAchievements(m.ranking.Achievements())
 		}
 		return m, nil
 	}
 	return m, nil
 }
 
+func (m Model) updateTrophies(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Quit):
+		return m, tea.Quit
+	case msg.Type == tea.KeyEscape, msg.Type == tea.KeyRunes && string(msg.Runes) == "a":
+		m.trophyMode = false
+	case key.Matches(msg, keys.Up):
+		m.trophies.MoveUp()
+	case key.Matches(msg, keys.Down):
+		m.trophies.MoveDown()
+	}
+	return m, nil
+}
+
 func (m *Model) renderRankingView() string {
 	header := m.renderHeader()
 	contentH := m.contentHeight()
 	panelFrameW := panelFocusedStyle.GetHorizontalFrameSize()
-	rankingContent := m.ranking.View(m.width-2-panelFrameW, contentH)
+	var rankingContent string
+	if m.trophyMode {
+		rankingContent = m.trophies.View(m.width-2-panelFrameW, contentH)
+	} else {
+		rankingContent = m.ranking.View(m.width-2-panelFrameW, contentH)
+	}
 
-	// Ranking HUD.
+	// Ranking HUD, trophy HUD or date range prompt.
 	footer := footerStyle.Render(renderRankingHUD())
+	switch {
+	case m.ranking.EditingRange():
+		footer = footerStyle.Render(m.ranking.renderRangePrompt())
+	case m.trophyMode:
+		footer = footerStyle.Render(renderTrophyHUD())
+	}
 
 	style := panelFocusedStyle.Width(m.width - 2).Height(contentH)
 	body := style.Render(rankingContent)
@@ -373,18 +540,83 @@ func renderRankingHUD() string {
 
 	nav := hudLabelNav.Render("[NAV]") + " " +
 		hudKey.Render("↑↓") + hudDesc.Render(" move  ") +
-		hudKey.Render("1/2/3") + hudDesc.Render(" tab  ") +
-		hudKey.Render("⇥") + hudDesc.Render(" next tab")
+		hudKey.Render("0-9") + hudDesc.Render(" tab  ") +
+		hudKey.Render("⇥") + hudDesc.Render(" next tab  ") +
+		hudKey.Render("⏎") + hudDesc.Render(" drill down")
 
 	cmd := hudLabelCmd.Render("[CMD]") + " " +
 		hudKey.Render("s") + hudDesc.Render(" scope  ") +
 		hudKey.Render("p") + hudDesc.Render(" period  ") +
+		hudKey.Render("d") + hudDesc.Render(" dates  ") +
+		hudKey.Render("g") + hudDesc.Render(" group  ") +
+		hudKey.Render("G") + hudDesc.Render(" grading  ") +
+		hudKey.Render("a") + hudDesc.Render(" trophies  ") +
 		hudKey.Render("r/Esc") + hudDesc.Render(" close  ") +
 		hudKey.Render("q") + hudDesc.Render(" quit")
 
 	return nav + sep + cmd
 }
 
+func (m Model) updateTimeline(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Quit):
+		return m, tea.Quit
+	case msg.Type == tea.KeyEscape, key.Matches(msg, keys.Timeline):
+		m.timelineMode = false
+		return m, nil
+	case key.Matches(msg, keys.Up):
+		m.timelineView.MoveUp()
+	case key.Matches(msg, keys.Down):
+		m.timelineView.MoveDown()
+	case key.Matches(msg, keys.PageUp):
+		m.timelineView.ScrollDetail(-m.contentHeight() / 2)
+	case key.Matches(msg, keys.PageDown):
+		m.timelineView.ScrollDetail(m.contentHeight() / 2)
+	}
+	return m, nil
+}
+
+func (m *Model) renderTimelineView() string {
+	header := m.renderHeader()
+	contentH := m.contentHeight()
+	panelFrameW := panelFocusedStyle.GetHorizontalFrameSize()
+	content := m.timelineView.View(m.width-2-panelFrameW, contentH)
+
+	footer := footerStyle.Render(renderTimelineHUD(m.timeline.Len()))
+
+	style := panelFocusedStyle.Width(m.width - 2).Height(contentH)
+	body := style.Render(content)
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, body, footer)
+}
+
+func renderTrophyHUD() string {
+	sep := hudSep.Render(" │ ")
+
+	nav := hudLabelNav.Render("[NAV]") + " " +
+		hudKey.Render("↑↓") + hudDesc.Render(" move")
+
+	cmd := hudLabelCmd.Render("[CMD]") + " " +
+		hudKey.Render("a/Esc") + hudDesc.Render(" back to ranking  ") +
+		hudKey.Render("q") + hudDesc.Render(" quit")
+
+	return nav + sep + cmd
+}
+
+func renderTimelineHUD(count int) string {
+	sep := hudSep.Render(" │ ")
+
+	nav := hudLabelNav.Render("[NAV]") + " " +
+		hudKey.Render("↑↓") + hudDesc.Render(" select  ") +
+		hudKey.Render("pgup/pgdn") + hudDesc.Render(" scroll diff")
+
+	cmd := hudLabelCmd.Render("[CMD]") + " " +
+		hudKey.Render("t/Esc") + hudDesc.Render(" close  ") +
+		hudKey.Render("q") + hudDesc.Render(" quit")
+
+	return nav + sep + cmd + sep + hudDesc.Render(fmt.Sprintf("%d changes", count))
+}
+
 func (m *Model) toggleFocus() {
 	if m.focus == PaneTree {
 		m.focus = PanePreview
@@ -404,6 +636,8 @@ func (m *Model) updateLayout() {
 	m.preview.SetHeight(h)
 	m.preview.PrepareCardContent(m.previewWidth())
 	m.ranking.SetHeight(h - rankingHeaderRows)
+	m.timelineView.SetHeight(h)
+	m.trophies.SetHeight(h - trophyHeaderRows)
 }
 
 func (m *Model) contentHeight() int {
@@ -422,6 +656,17 @@ func (m *Model) treeWidth() int {
 	return w
 }
 
+// watchLabel describes the active watcher backend for the HUD (empty when not watching).
+func (m *Model) watchLabel() string {
+	if m.watcher == nil {
+		return ""
+	}
+	if mode := m.watcher.Mode(); mode == watcher.ModePoll {
+		return fmt.Sprintf("%s %s", mode, m.watcher.Interval())
+	}
+	return m.watcher.Mode().String()
+}
+
 // waitCmd returns a command to wait for the next file change if the watcher is active.
 func (m *Model) waitCmd() tea.Cmd {
 	if m.watcher != nil {
@@ -430,38 +675,67 @@ func (m *Model) waitCmd() tea.Cmd {
 	return nil
 }
 
-// handleFileChanged performs a rescan and rebuilds the tree when a file change is detected.
-func (m Model) handleFileChanged() (tea.Model, tea.Cmd) {
-	if m.sc == nil {
+// handleFileChanged rescans the entries affected by the changed paths and updates
+// only those tree nodes, merge layers and the preview if it shows an affected file.
+func (m Model) handleFileChanged(msg watcher.FileChangedMsg) (tea.Model, tea.Cmd) {
+	if m.sc == nil || len(msg.Changes) == 0 {
 		return m, m.waitCmd()
 	}
+	changed := msg.Paths()
 
-	// Capture tree state.
-	state := m.tree.CaptureState()
-
-	// Rescan.
+	// Rescan affected entries.
 	start := time.Now()
-	result, err := m.sc.Scan()
+	result, updated, err := m.sc.Rescan(m.scan, changed)
 	scanDuration := time.Since(start)
 	if err != nil {
 		return m, m.waitCmd()
 	}
 
-	// Rebuild tree and restore state.
 	m.scan = result
 	m.scanDuration = scanDuration
-	m.tree = NewTreeModel(result)
-	m.tree.RestoreState(state)
+	m.ranking.collector.MCPServers = configuredMCPServers(result)
+	m.ranking.collector.Commands = customCommandNames(result)
+	m.ranking.collector.Config = configItems(result)
+	m.tree.UpdateFiles(result, updated)
 	m.tree.SetHeight(m.contentHeight())
-
-	// Update merge.
-	m.merged = merger.Merge(result)
+	m.tree.Highlight(changed)
+
+	// Update merge and record the edits with the effective settings they changed.
+	before := m.merged.Values
+	m.merged.Update(result, updated)
+	m.timeline.Record(time.Now(), changed, merger.Diff(before, m.merged.Values))
+	if m.timelineMode {
+		m.timelineView.Load()
+	}
 
 	// Update preview.
-	m.preview.InvalidateCache()
+	m.preview.InvalidatePaths(changed)
 	m.syncPreview()
 
-	return m, m.waitCmd()
+	// Show a notification, cleared after noticeDuration.
+	m.noticeSeq++
+	m.notice = changeNotice(msg.Changes)
+	seq := m.noticeSeq
+	clear := tea.Tick(noticeDuration, func(time.Time) tea.Msg { return clearNoticeMsg{seq: seq} })
+
+	return m, tea.Batch(m.waitCmd(), clear)
+}
+
+// changeNotice formats a short notification such as "changed: settings.json (+2)".
+func changeNotice(changes []watcher.Change) string {
+	c := changes[0]
+	verb := "changed"
+	switch c.Op {
+	case watcher.OpCreated:
+		verb = "created"
+	case watcher.OpRemoved:
+		verb = "removed"
+	}
+	notice := fmt.Sprintf("%s: %s", verb, filepath.Base(c.Path))
+	if len(changes) > 1 {
+		notice += fmt.Sprintf(" (+%d)", len(changes)-1)
+	}
+	return notice
 }
 
 func (m *Model) previewWidth() int {
@@ -14,13 +14,14 @@ import (
 
 // PreviewModel manages the state of the right preview panel.
 type PreviewModel struct {
-	file       *model.ConfigFile // Currently displayed file.
-	content    string            // File content.
-	lines      []string          // Content split by line.
-	offset     int               // Scroll offset.
-	height     int               // Number of visible rows.
-	isCardMode bool              // Card mode (agents/skills directory).
-	lastWidth  int               // Last width used in card mode.
+	file          *model.ConfigFile // Currently displayed file.
+	content       string            // File content.
+	lines         []string          // Content split by line.
+	offset        int               // Scroll offset.
+	height        int               // Number of visible rows.
+	isCardMode    bool              // Card mode (agents/skills directory).
+	lastWidth     int               // Last width used in card mode.
+	revealSecrets bool              // Whether secret values are shown unmasked.
 }
 
 // SetFile sets the file to display in the preview.
@@ -83,14 +84,22 @@ func (p *PreviewModel) SetFile(file *model.ConfigFile) {
 	}
 
 	raw := string(data)
-	switch file.FileType {
-	case model.FileTypeJSON, model.FileTypeJSONC:
+	var table string
+	if file.Category == model.CategoryKeybindings {
+		table = renderKeybindings(raw)
+	}
+
+	switch {
+	case table != "":
+		p.content = table
+	case file.FileType == model.FileTypeJSON, file.FileType == model.FileTypeJSONC:
 		p.content = parser.FormatJSON(raw)
-	case model.FileTypeMarkdown:
+	case file.FileType == model.FileTypeMarkdown:
 		p.content = parser.FormatMarkdown(raw)
 	default:
 		p.content = raw
 	}
+	p.content = renderSecrets(file) + renderDiagnostics(file.Diagnostics) + p.content
 	p.lines = strings.Split(p.content, "\n")
 }
 
@@ -130,9 +139,31 @@ func dirIcon(isDir bool) string {
 	return "📄"
 }
 
+// ToggleSecrets toggles masking of secret values and forces the next SetFile to re-render.
+func (p *PreviewModel) ToggleSecrets() {
+	p.revealSecrets = !p.revealSecrets
+	p.InvalidateCache()
+}
+
 // InvalidateCache invalidates the cached file so the next SetFile call forces a refresh.
 func (p *PreviewModel) InvalidateCache() { p.file = nil }
 
+// InvalidatePaths invalidates the cache if the displayed file is affected by any changed path:
+// the file itself (virtual sections included), a file inside a displayed directory, or an ancestor.
+func (p *PreviewModel) InvalidatePaths(changed []string) {
+	if p.file == nil {
+		return
+	}
+	path, _, _ := strings.Cut(p.file.Path, "#")
+	sep := string(filepath.Separator)
+	for _, c := range changed {
+		if c == path || strings.HasPrefix(c, path+sep) || strings.HasPrefix(path, c+sep) {
+			p.InvalidateCache()
+			return
+		}
+	}
+}
+
 // ScrollUp scrolls the preview up by n lines.
 func (p *PreviewModel) ScrollUp(n int) {
 	p.offset -= n
@@ -466,6 +497,13 @@ func (p *PreviewModel) renderVirtualNode(file *model.ConfigFile) string {
 		return fmt.Sprintf("(failed to read: %v)", err)
 	}
 
+	switch file.Category {
+	case model.CategoryHooks:
+		return renderHooksSection(string(data), dotPath)
+	case model.CategoryMCP:
+		return renderMCPSection(string(data), dotPath, p.revealSecrets)
+	}
+
 	cleaned := parser.StripJSONC(string(data))
 	var obj any
 	if err := json.Unmarshal([]byte(cleaned), &obj); err != nil {
@@ -1,16 +1,51 @@
 package tui
 
 import (
+	"context"
 	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/jeremy-kr/ccfg/internal/model"
 	"github.com/jeremy-kr/ccfg/internal/usage"
 )
 
+// rankingPollInterval is how often transcripts are tailed while the ranking view is open.
+const rankingPollInterval = 2 * time.Second
+
+// promotionHighlight is how long a newly promoted grade stays highlighted.
+const promotionHighlight = 4 * time.Second
+
+// rankingTailMsg carries usage appended to transcripts since the previous poll.
+type rankingTailMsg struct {
+	loop  int          // Poll loop the message belongs to (stale loops stop).
+	gen   int          // Data generation the delta applies to (stale deltas are dropped).
+	delta usage.Counts // Newly observed invocations.
+}
+
+// rankingLoadedMsg carries the usage collected by a Load.
+type rankingLoadedMsg struct {
+	gen     int                // Data generation the result belongs to (stale results are dropped).
+	data    *usage.UsageData   // Collected usage; nil on error.
+	err     error              // Collection error.
+	tailer  *usage.Tailer      // Follows transcripts appended after the collection.
+	grading usage.GradingModel // Grading model the data was ranked with.
+}
+
+// sparkWidth is the number of columns of the per-row usage sparkline.
+const sparkWidth = 10
+
+// sparkTicks are the sparkline levels; zero days are blank.
+var sparkTicks = []rune(" ▁▂▃▄▅▆▇█")
+
 // rankingHeaderRows is the number of rows consumed by the ranking header
-// (tab bar + scope bar + period bar + separator).
-const rankingHeaderRows = 4
+// (tab bar + scope bar + period bar + grading bar + separator).
+const rankingHeaderRows = 5
 
 // RankingModel manages the state of the ranking view.
 type RankingModel struct {
@@ -18,11 +53,24 @@ type RankingModel struct {
 	tab       usage.RankCategory
 	scope     usage.DataScope
 	period    usage.TimePeriod
+	dateRange usage.DateRange    // Explicit range overriding period (zero uses period).
+	rangeEdit *rangePrompt       // Open date range prompt (nil when closed).
+	group     usage.TokenGroup   // Breakdown shown on the Tokens tab.
+	sessions  usage.SessionGroup // Breakdown shown on the Sessions tab.
+	hotspots  usage.HotspotGroup // Breakdown shown on the Hotspots tab.
+	drill     string             // Row drilled into: an MCP server, a session group or a failing tool ("" lists the top level).
 	cursor    int
 	offset    int
 	height    int
 	collector *usage.Collector
 	err       error
+	loading   bool                 // Set while a Load is collecting.
+	cancel    context.CancelFunc   // Cancels the Load in progress (nil when none).
+	tailer    *usage.Tailer        // Follows transcripts appended after Load.
+	gen       int                  // Incremented on every Load.
+	promoted  map[string]time.Time // "category/name" -> highlight expiry.
+	priceErr  error                // Set when the price table could not be loaded (defaults are used).
+	gradeErr  error                // Set when the grading settings could not be loaded (log grading is used).
 }
 
 // NewRankingModel creates a RankingModel with the given Collector.
@@ -34,14 +82,103 @@ func NewRankingModel(collector *usage.Collector) RankingModel {
 	}
 }
 
-// Load collects usage data.
-func (r *RankingModel) Load() {
-	r.collector.Period = r.period
-	data, err := r.collector.Collect(r.scope)
-	r.data = data
-	r.err = err
+// Load cancels any collection in progress and returns a command that collects
+// usage data in the background. The view shows a loading state until Loaded
+// receives the result.
+func (r *RankingModel) Load() tea.Cmd {
+	r.Cancel()
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	// Collect from a copy so later settings changes do not race the collection.
+	c := *r.collector
+	c.Period = r.period
+	c.Range = r.dateRange
+	scope := r.scope
+
+	r.data = nil
+	r.err = nil
+	r.loading = true
 	r.cursor = 0
 	r.offset = 0
+	r.drill = ""
+	r.gen++
+	r.promoted = nil
+	r.tailer = nil
+	gen := r.gen
+	return func() tea.Msg {
+		data, err := c.CollectContext(ctx, scope)
+		msg := rankingLoadedMsg{gen: gen, data: data, err: err, grading: c.Grading.Model}
+		if err == nil {
+			msg.tailer = c.Tail(scope)
+		}
+		return msg
+	}
+}
+
+// Loaded applies the result of a Load. It reports false for the result of a
+// superseded Load, which is dropped.
+func (r *RankingModel) Loaded(msg rankingLoadedMsg) bool {
+	if msg.gen != r.gen {
+		return false
+	}
+	r.Cancel()
+	r.loading = false
+	r.data = msg.data
+	r.err = msg.err
+	r.tailer = msg.tailer
+	// The grading model may have been cycled while the data was collected.
+	if r.data != nil && msg.grading != r.collector.Grading.Model {
+		r.data.SetGrading(r.collector.Grading)
+	}
+	return true
+}
+
+// Cancel stops the collection in progress, if any.
+func (r *RankingModel) Cancel() {
+	if r.cancel != nil {
+		r.cancel()
+		r.cancel = nil
+	}
+}
+
+// PollCmd schedules the next transcript tail poll for the given loop.
+func (r *RankingModel) PollCmd(loop int) tea.Cmd {
+	tailer, gen := r.tailer, r.gen
+	if tailer == nil {
+		return nil
+	}
+	return tea.Tick(rankingPollInterval, func(time.Time) tea.Msg {
+		return rankingTailMsg{loop: loop, gen: gen, delta: tailer.Poll()}
+	})
+}
+
+// ApplyTail merges a tail delta into the rankings and highlights promoted grades.
+func (r *RankingModel) ApplyTail(msg rankingTailMsg) {
+	if msg.gen != r.gen || r.data == nil {
+		return
+	}
+	promotions := r.data.Add(msg.delta)
+	if len(promotions) == 0 {
+		return
+	}
+	if r.promoted == nil {
+		r.promoted = make(map[string]time.Time)
+	}
+	until := time.Now().Add(promotionHighlight)
+	for _, p := range promotions {
+		r.promoted[promotionKey(p.Category, p.Name)] = until
+	}
+}
+
+// isPromoted reports whether the entry's grade was promoted recently.
+func (r *RankingModel) isPromoted(name string) bool {
+	until, ok := r.promoted[promotionKey(r.tab, name)]
+	return ok && time.Now().Before(until)
+}
+
+func promotionKey(cat usage.RankCategory, name string) string {
+	return cat.String() + "/" + name
 }
 
 // SetHeight sets the number of visible rows.
@@ -61,6 +198,29 @@ func (r *RankingModel) entries() []usage.RankEntry {
 		return r.data.Tools
 	case usage.RankSkills:
 		return r.data.Skills
+	case usage.RankTokens:
+		return r.data.Tokens[r.group]
+	case usage.RankCommands:
+		return r.data.Commands
+	case usage.RankMCP:
+		if r.drill != "" {
+			return r.data.MCPTools[r.drill]
+		}
+		return r.data.MCP
+	case usage.RankSessions:
+		if r.drill != "" {
+			return r.data.SessionsFor(r.sessions, r.drill)
+		}
+		return r.data.SessionRanks[r.sessions]
+	case usage.RankUnused:
+		return r.data.Unused
+	case usage.RankErrors:
+		if r.drill != "" {
+			return r.data.ErrorSnippets[r.drill]
+		}
+		return r.data.ToolErrors
+	case usage.RankHotspots:
+		return r.data.Hotspots[r.hotspots]
 	default:
 		return nil
 	}
@@ -68,32 +228,175 @@ func (r *RankingModel) entries() []usage.RankEntry {
 
 // NextTab moves to the next tab.
 func (r *RankingModel) NextTab() {
-	r.tab = (r.tab + 1) % 3
-	r.cursor = 0
-	r.offset = 0
+	r.SetTab(r.tab.Next())
 }
 
 // SetTab sets the tab directly.
 func (r *RankingModel) SetTab(tab usage.RankCategory) {
 	r.tab = tab
+	r.drill = ""
 	r.cursor = 0
 	r.offset = 0
 }
 
-// ToggleScope toggles the scope and reloads data.
-func (r *RankingModel) ToggleScope() {
-	if r.scope == usage.ScopeAll {
-		r.scope = usage.ScopeProject
-	} else {
-		r.scope = usage.ScopeAll
+// Drill opens the tool ranking of the selected MCP server, the sessions behind the
+// selected Sessions row, or the error messages of the selected tool. It reports
+// whether the view changed (only rows with usage can be opened).
+func (r *RankingModel) Drill() bool {
+	entries := r.entries()
+	drillable := r.tab == usage.RankMCP || r.tab == usage.RankSessions || r.tab == usage.RankErrors
+	if !drillable || r.drill != "" || r.cursor >= len(entries) || entries[r.cursor].Count == 0 {
+		return false
+	}
+	r.drill = entries[r.cursor].Name
+	r.cursor = 0
+	r.offset = 0
+	return true
+}
+
+// Back leaves a drill-down, reselecting the row it was opened from.
+// It reports false if no drill-down was open.
+func (r *RankingModel) Back() bool {
+	if r.drill == "" {
+		return false
+	}
+	row := r.drill
+	r.drill = ""
+	r.cursor = 0
+	r.offset = 0
+	for i, e := range r.entries() {
+		if e.Name == row {
+			r.cursor = i
+			r.adjustScroll()
+			break
+		}
+	}
+	return true
+}
+
+// CycleGroup switches the Tokens tab to the next breakdown (Model → Project → Day → Agent),
+// the Sessions tab (Project → Day → Hour) or the Hotspots tab (Bash → Reads → Edits → Domains).
+func (r *RankingModel) CycleGroup() {
+	switch r.tab {
+	case usage.RankSessions:
+		r.sessions = r.sessions.Next()
+		r.drill = ""
+	case usage.RankHotspots:
+		r.hotspots = r.hotspots.Next()
+	default:
+		r.group = r.group.Next()
+	}
+	r.cursor = 0
+	r.offset = 0
+}
+
+// ToggleScope cycles through scopes: All → Project → Project+ → All, and reloads data.
+func (r *RankingModel) ToggleScope() tea.Cmd {
+	r.scope = r.scope.Next()
+	return r.Load()
+}
+
+// Achievements returns the achievements of the loaded data.
+func (r *RankingModel) Achievements() []usage.Achievement {
+	if r.data == nil {
+		return nil
+	}
+	return r.data.Achievements
+}
+
+// CycleGrading switches to the next grading model and regrades the loaded data in place.
+func (r *RankingModel) CycleGrading() {
+	g := r.collector.Grading
+	g.Model = g.Model.Next()
+	r.collector.Grading = g
+	r.gradeErr = nil
+	if r.data != nil {
+		r.data.SetGrading(g)
 	}
-	r.Load()
 }
 
 // TogglePeriod cycles through time periods: All → 30d → 7d → 24h → All.
-func (r *RankingModel) TogglePeriod() {
-	r.period = r.period.Next()
-	r.Load()
+// With a date range set, it clears the range and returns to the current period.
+func (r *RankingModel) TogglePeriod() tea.Cmd {
+	if r.dateRange.IsZero() {
+		r.period = r.period.Next()
+	}
+	r.dateRange = usage.DateRange{}
+	return r.Load()
+}
+
+// SetRange shows usage within an explicit date range instead of the period.
+// A zero range returns to the period.
+func (r *RankingModel) SetRange(dr usage.DateRange) tea.Cmd {
+	r.dateRange = dr
+	return r.Load()
+}
+
+// rangePrompt is the text typed into the date range prompt.
+type rangePrompt struct {
+	text   string
+	preset int   // Next entry of usage.Presets offered by Tab.
+	err    error // Parse error of the last Enter.
+}
+
+// OpenRangePrompt opens the date range prompt, prefilled with the active range.
+func (r *RankingModel) OpenRangePrompt() {
+	p := &rangePrompt{}
+	if !r.dateRange.IsZero() {
+		p.text = r.dateRange.String()
+	}
+	r.rangeEdit = p
+}
+
+// EditingRange reports whether the date range prompt is open.
+func (r *RankingModel) EditingRange() bool {
+	return r.rangeEdit != nil
+}
+
+// UpdateRangePrompt handles a key typed into the date range prompt: Enter applies
+// the range (an empty one returns to the period), Tab cycles the presets and Esc cancels.
+// It returns the reload command when a range was applied.
+func (r *RankingModel) UpdateRangePrompt(msg tea.KeyMsg) tea.Cmd {
+	p := r.rangeEdit
+	switch msg.Type {
+	case tea.KeyEscape:
+		r.rangeEdit = nil
+	case tea.KeyEnter:
+		var dr usage.DateRange
+		if strings.TrimSpace(p.text) != "" {
+			var err error
+			if dr, err = usage.ParseRange(p.text, time.Now()); err != nil {
+				p.err = err
+				return nil
+			}
+		}
+		r.rangeEdit = nil
+		return r.SetRange(dr)
+	case tea.KeyTab:
+		p.text = usage.Presets[p.preset]
+		p.preset = (p.preset + 1) % len(usage.Presets)
+		p.err = nil
+	case tea.KeyBackspace:
+		if runes := []rune(p.text); len(runes) > 0 {
+			p.text = string(runes[:len(runes)-1])
+		}
+		p.err = nil
+	case tea.KeyRunes, tea.KeySpace:
+		p.text += string(msg.Runes)
+		p.err = nil
+	}
+	return nil
+}
+
+// renderRangePrompt renders the open date range prompt and its parse error.
+func (r *RankingModel) renderRangePrompt() string {
+	prompt := lipgloss.NewStyle().Foreground(colorMagenta).Render(
+		fmt.Sprintf("📅 %s█  (e.g. 2026-10-01..2026-10-15, Tab: presets, Enter: apply, Esc: cancel)", r.rangeEdit.text),
+	)
+	if r.rangeEdit.err != nil {
+		prompt += "  " + lipgloss.NewStyle().Foreground(colorRed).Render(r.rangeEdit.err.Error())
+	}
+	return prompt
 }
 
 // MoveUp moves the cursor up.
@@ -143,11 +446,20 @@ func (r *RankingModel) View(width, height int) string {
 	b.WriteString(r.renderPeriodBar(width))
 	b.WriteString("\n")
 
+	// Grading bar.
+	b.WriteString(r.renderGradingBar(width))
+	b.WriteString("\n")
+
 	// Separator.
 	sep := lipgloss.NewStyle().Foreground(colorDimGray).Render(strings.Repeat("─", width-4))
 	b.WriteString(sep)
 	b.WriteString("\n")
 
+	if r.loading {
+		b.WriteString(lipgloss.NewStyle().Foreground(colorDimGray).Render("  Loading usage…"))
+		return b.String()
+	}
+
 	// Error display.
 	if r.err != nil {
 		errMsg := lipgloss.NewStyle().Foreground(colorRed).Render(fmt.Sprintf("Error: %v", r.err))
@@ -176,7 +488,22 @@ func (r *RankingModel) View(width, height int) string {
 	scrollBars := renderScrollbar(len(entries), visibleRows, r.offset)
 
 	contentW := width
-	barWidth := width - 35 // rank(4) + grade(6) + name(15) + count(6) + padding(4).
+	barWidth := width - 35 - sparkWidth - 1 // rank(4) + grade(6) + name(15) + sparkline + count(6) + padding(4).
+	if r.data != nil && r.data.HasPrior {
+		barWidth -= 6 // Change against the prior period.
+	}
+	switch {
+	case r.tab == usage.RankTokens:
+		barWidth -= 10 // Compact token count plus estimated cost.
+	case r.tab == usage.RankSessions && r.drill != "":
+		barWidth -= 16 // Messages, duration and project.
+	case r.tab == usage.RankSessions:
+		barWidth -= 7 // Count unit.
+	case r.tab == usage.RankUnused:
+		barWidth -= 30 // Last use, kind and path.
+	case r.tab == usage.RankErrors:
+		barWidth -= 10 // Calls and error rate.
+	}
 	if scrollBars != nil {
 		contentW = width - 1
 		barWidth--
@@ -190,6 +517,9 @@ func (r *RankingModel) View(width, height int) string {
 		selected := i == r.cursor
 
 		line := r.renderEntry(i+1, entry, barWidth, selected)
+		if r.isPromoted(entry.Name) {
+			line += changedStyle.Render(" ▲ rank up!")
+		}
 		if scrollBars != nil {
 			if gap := contentW - lipgloss.Width(line); gap > 0 {
 				line += strings.Repeat(" ", gap)
@@ -214,28 +544,45 @@ func (r *RankingModel) renderTabs(width int) string {
 		{usage.RankAgents, "🤖", "Agents"},
 		{usage.RankTools, "🔧", "Tools"},
 		{usage.RankSkills, "🧠", "Skills"},
+		{usage.RankTokens, "💰", "Tokens"},
+		{usage.RankMCP, "🔌", "MCP"},
+		{usage.RankCommands, "⌨️", "Commands"},
+		{usage.RankSessions, "🕒", "Sessions"},
+		{usage.RankUnused, "🧹", "Unused"},
+		{usage.RankErrors, "🚨", "Errors"},
+		{usage.RankHotspots, "🔥", "Hotspots"},
 	}
 
-	var parts []string
-	for _, t := range tabs {
-		label := fmt.Sprintf(" %s %s ", t.emoji, t.label)
-		if t.cat == r.tab {
-			parts = append(parts, lipgloss.NewStyle().
-				Bold(true).
-				Foreground(colorYellow).
-				Background(lipgloss.Color("#333333")).
-				Render(label))
-		} else {
+	hint := hudDesc.Render("0-9: tab  Tab: next")
+	render := func(compact bool) string {
+		var parts []string
+		for _, t := range tabs {
+			label := fmt.Sprintf(" %s %s ", t.emoji, t.label)
+			if t.cat == r.tab {
+				parts = append(parts, lipgloss.NewStyle().
+					Bold(true).
+					Foreground(colorYellow).
+					Background(lipgloss.Color("#333333")).
+					Render(label))
+				continue
+			}
+			if compact {
+				label = fmt.Sprintf(" %s ", t.emoji)
+			}
 			parts = append(parts, lipgloss.NewStyle().
 				Foreground(colorDimGray).
 				Render(label))
 		}
+		return strings.Join(parts, lipgloss.NewStyle().Foreground(colorDimGray).Render(" │ "))
 	}
 
-	tabBar := strings.Join(parts, lipgloss.NewStyle().Foreground(colorDimGray).Render(" │ "))
+	// Inactive tabs drop their labels when all of them do not fit.
+	tabBar := render(false)
+	if lipgloss.Width(tabBar)+lipgloss.Width(hint)+5 > width {
+		tabBar = render(true)
+	}
 
 	// Place key hints on the right.
-	hint := hudDesc.Render("1/2/3: tab  Tab: next")
 	pad := width - lipgloss.Width(tabBar) - lipgloss.Width(hint) - 4
 	if pad < 1 {
 		pad = 1
@@ -247,14 +594,26 @@ func (r *RankingModel) renderScopeBar(width int) string {
 	activeStyle := lipgloss.NewStyle().Bold(true).Background(lipgloss.Color("#333333"))
 	inactiveStyle := lipgloss.NewStyle().Foreground(colorDimGray)
 
-	allStyle, projStyle := inactiveStyle, activeStyle.Foreground(colorCyan)
-	if r.scope == usage.ScopeAll {
-		allStyle, projStyle = activeStyle.Foreground(colorYellow), inactiveStyle
+	scopes := []usage.DataScope{usage.ScopeAll, usage.ScopeProject, usage.ScopeProjectTree}
+	var parts []string
+	for _, s := range scopes {
+		label := fmt.Sprintf(" %s ", s)
+		switch {
+		case s != r.scope:
+			parts = append(parts, inactiveStyle.Render(label))
+		case s == usage.ScopeAll:
+			parts = append(parts, activeStyle.Foreground(colorYellow).Render(label))
+		default:
+			parts = append(parts, activeStyle.Foreground(colorCyan).Render(label))
+		}
 	}
 
-	scopeBar := hudDesc.Render("Scope: ") + allStyle.Render(" All ") + hudDesc.Render(" / ") + projStyle.Render(" Project ")
+	scopeBar := hudDesc.Render("Scope: ") + strings.Join(parts, hudDesc.Render(" / "))
 
-	hint := hudDesc.Render("s: scope  p: period")
+	hint := hudDesc.Render("s: scope  p: period  d: dates")
+	if r.tab == usage.RankTokens || r.tab == usage.RankSessions || r.tab == usage.RankHotspots {
+		hint = hudDesc.Render("s: scope  p: period  d: dates  g: group")
+	}
 	pad := width - lipgloss.Width(scopeBar) - lipgloss.Width(hint) - 4
 	if pad < 1 {
 		pad = 1
@@ -262,6 +621,33 @@ func (r *RankingModel) renderScopeBar(width int) string {
 	return scopeBar + strings.Repeat(" ", pad) + hint
 }
 
+func (r *RankingModel) renderGradingBar(width int) string {
+	activeStyle := lipgloss.NewStyle().Bold(true).Background(lipgloss.Color("#333333"))
+	inactiveStyle := lipgloss.NewStyle().Foreground(colorDimGray)
+
+	models := []usage.GradingModel{usage.GradingLog, usage.GradingPercentile, usage.GradingAbsolute, usage.GradingZScore}
+	var parts []string
+	for _, m := range models {
+		label := fmt.Sprintf(" %s ", m)
+		if m == r.collector.Grading.Model {
+			parts = append(parts, activeStyle.Foreground(colorYellow).Render(label))
+		} else {
+			parts = append(parts, inactiveStyle.Render(label))
+		}
+	}
+	gradingBar := hudDesc.Render("Grade: ") + strings.Join(parts, hudDesc.Render(" / "))
+
+	hint := hudDesc.Render(r.collector.Grading.Describe() + "  G: grading")
+	if r.gradeErr != nil {
+		hint = lipgloss.NewStyle().Foreground(colorRed).Render("grading: " + r.gradeErr.Error())
+	}
+	pad := width - lipgloss.Width(gradingBar) - lipgloss.Width(hint) - 4
+	if pad < 1 {
+		pad = 1
+	}
+	return gradingBar + strings.Repeat(" ", pad) + hint
+}
+
 func (r *RankingModel) renderPeriodBar(width int) string {
 	activeStyle := lipgloss.NewStyle().Bold(true).Background(lipgloss.Color("#333333"))
 	inactiveStyle := lipgloss.NewStyle().Foreground(colorDimGray)
@@ -278,16 +664,365 @@ func (r *RankingModel) renderPeriodBar(width int) string {
 	}
 
 	periodBar := hudDesc.Render("Period: ") + strings.Join(parts, hudDesc.Render(" / "))
-	return periodBar
+	if !r.dateRange.IsZero() {
+		periodBar = hudDesc.Render("Range: ") + activeStyle.Foreground(colorYellow).Render(" "+r.dateRange.String()+" ")
+	}
+	switch {
+	case r.tab == usage.RankMCP:
+		periodBar += r.renderMCPPath()
+	case r.tab == usage.RankErrors && r.drill == "":
+		periodBar += hudDesc.Render("   Tools  (Enter: messages)")
+	case r.tab == usage.RankErrors:
+		periodBar += hudDesc.Render("   Tools › ") + hudKey.Render(r.drill) + hudDesc.Render("  (Esc: back)")
+	}
+	if r.tab == usage.RankSessions {
+		return r.renderSessionsBar(periodBar, width)
+	}
+	if r.tab == usage.RankHotspots {
+		return r.renderHotspotsBar(periodBar, width)
+	}
+	if r.tab == usage.RankUnused && r.data != nil {
+		summary := hudDesc.Render(fmt.Sprintf("%d of %d configured items used ≤%d times",
+			len(r.data.Unused), len(r.collector.Config), usage.LowUsage))
+		pad := width - lipgloss.Width(periodBar) - lipgloss.Width(summary) - 4
+		if pad < 1 {
+			pad = 1
+		}
+		return periodBar + strings.Repeat(" ", pad) + summary
+	}
+	if r.tab != usage.RankTokens {
+		if r.data == nil || !r.data.HasPrior {
+			return periodBar
+		}
+		hint := hudDesc.Render(fmt.Sprintf("Δ vs prior %s", r.period))
+		if !r.dateRange.IsZero() {
+			hint = hudDesc.Render("Δ vs prior period")
+		}
+		pad := width - lipgloss.Width(periodBar) - lipgloss.Width(hint) - 4
+		if pad < 1 {
+			pad = 1
+		}
+		return periodBar + strings.Repeat(" ", pad) + hint
+	}
+
+	var groups []fmt.Stringer
+	for g := usage.TokensByModel; ; {
+		groups = append(groups, g)
+		if g = g.Next(); g == usage.TokensByModel {
+			break
+		}
+	}
+	periodBar += renderGroups(groups, r.group)
+
+	var summary string
+	switch {
+	case r.priceErr != nil:
+		summary = lipgloss.NewStyle().Foreground(colorRed).Render("price table: " + r.priceErr.Error())
+	case r.data != nil:
+		summary = hudDesc.Render(fmt.Sprintf("Σ %s ≈ $%.2f", formatTokens(r.data.TokenTotal.Total()), r.data.CostTotal))
+	}
+	pad := width - lipgloss.Width(periodBar) - lipgloss.Width(summary) - 4
+	if pad < 1 {
+		pad = 1
+	}
+	return periodBar + strings.Repeat(" ", pad) + summary
+}
+
+// renderSessionsBar adds the session grouping, or the drill-down location, and the
+// session totals to the period bar.
+func (r *RankingModel) renderSessionsBar(periodBar string, width int) string {
+	if r.drill != "" {
+		periodBar += hudDesc.Render(fmt.Sprintf("   %s › ", r.sessions)) + hudKey.Render(r.drill) + hudDesc.Render("  (Esc: back)")
+	} else {
+		var groups []fmt.Stringer
+		for g := usage.SessionsByProject; ; {
+			groups = append(groups, g)
+			if g = g.Next(); g == usage.SessionsByProject {
+				break
+			}
+		}
+		periodBar += renderGroups(groups, r.sessions)
+	}
+	if r.data == nil {
+		return periodBar
+	}
+	stats := r.data.SessionStats
+	summary := hudDesc.Render(fmt.Sprintf("%d sessions · avg %s · %.1f msgs",
+		stats.Count, formatDuration(stats.AvgDuration()), stats.AvgMessages()))
+	pad := width - lipgloss.Width(periodBar) - lipgloss.Width(summary) - 4
+	if pad < 1 {
+		pad = 1
+	}
+	return periodBar + strings.Repeat(" ", pad) + summary
+}
+
+// renderHotspotsBar adds the hotspot grouping and the allow rule for the selected
+// row to the period bar.
+func (r *RankingModel) renderHotspotsBar(periodBar string, width int) string {
+	var groups []fmt.Stringer
+	for g := usage.HotspotsBash; ; {
+		groups = append(groups, g)
+		if g = g.Next(); g == usage.HotspotsBash {
+			break
+		}
+	}
+	periodBar += renderGroups(groups, r.hotspots)
+
+	entries := r.entries()
+	if r.cursor >= len(entries) {
+		return periodBar
+	}
+	summary := hudDesc.Render("allow: ") + hudKey.Render(usage.AllowRule(r.hotspots, entries[r.cursor].Name))
+	pad := width - lipgloss.Width(periodBar) - lipgloss.Width(summary) - 4
+	if pad < 1 {
+		pad = 1
+	}
+	return periodBar + strings.Repeat(" ", pad) + summary
+}
+
+// renderGroups renders a "By:" selector over groups with active highlighted.
+func renderGroups(groups []fmt.Stringer, active fmt.Stringer) string {
+	activeStyle := lipgloss.NewStyle().Bold(true).Background(lipgloss.Color("#333333")).Foreground(colorYellow)
+	inactiveStyle := lipgloss.NewStyle().Foreground(colorDimGray)
+	parts := make([]string, len(groups))
+	for i, g := range groups {
+		label := fmt.Sprintf(" %s ", g)
+		if g == active {
+			parts[i] = activeStyle.Render(label)
+		} else {
+			parts[i] = inactiveStyle.Render(label)
+		}
+	}
+	return hudDesc.Render("  By: ") + strings.Join(parts, hudDesc.Render(" / "))
+}
+
+// renderMCPPath shows the drill-down location on the MCP tab.
+func (r *RankingModel) renderMCPPath() string {
+	if r.drill == "" {
+		return hudDesc.Render("   Servers  (Enter: tools)")
+	}
+	return hudDesc.Render("   Servers › ") + hudKey.Render(r.drill) + hudDesc.Render("  (Esc: back)")
+}
+
+// countLabel formats an entry's count; token entries show a compact total and estimated cost.
+func (r *RankingModel) countLabel(entry usage.RankEntry) string {
+	if r.tab == usage.RankTokens {
+		return fmt.Sprintf("%s ≈$%.2f", formatTokens(int64(entry.Count)), entry.Cost)
+	}
+	if r.tab == usage.RankMCP && entry.Count == 0 {
+		return "0 (unused)"
+	}
+	if r.tab == usage.RankSessions {
+		switch {
+		case entry.Session != nil:
+			return fmt.Sprintf("%d msgs  %s  %s", entry.Count, formatDuration(entry.Session.Duration()), entry.Session.Project)
+		case r.sessions == usage.SessionsByHour:
+			return fmt.Sprintf("%d msgs", entry.Count)
+		case entry.Count == 1:
+			return "1 session"
+		default:
+			return fmt.Sprintf("%d sessions", entry.Count)
+		}
+	}
+	if r.tab == usage.RankUnused && entry.Item != nil {
+		last := "never"
+		if !entry.LastUsed.IsZero() {
+			last = entry.LastUsed.Local().Format("2006-01-02")
+		}
+		return fmt.Sprintf("%d  %-10s  %-7s  %s", entry.Count, last, itemKinds[entry.Item.Category], tildePath(entry.Item.Path, r.collector.HomeDir))
+	}
+	if r.tab == usage.RankErrors && r.drill == "" {
+		return fmt.Sprintf("%d/%d  %.1f%%", entry.Count, entry.Calls, entry.ErrorRate()*100)
+	}
+	if r.tab == usage.RankCommands {
+		if entry.Custom {
+			return fmt.Sprintf("%d  custom", entry.Count)
+		}
+		return fmt.Sprintf("%d  built-in", entry.Count)
+	}
+	return fmt.Sprintf("%d", entry.Count)
+}
+
+// deltaLabel formats an entry's change against the prior period, e.g. "+42%",
+// or "new" if it was not used then.
+func deltaLabel(entry usage.RankEntry) string {
+	if change, ok := entry.Change(); ok {
+		return fmt.Sprintf("%+.0f%%", change*100)
+	}
+	if entry.Count > 0 {
+		return "new"
+	}
+	return ""
+}
+
+// renderDelta colors deltaLabel: green for growth, red for decline.
+func (r *RankingModel) renderDelta(entry usage.RankEntry) string {
+	if r.data == nil || !r.data.HasPrior || entry.Session != nil {
+		return ""
+	}
+	label := fmt.Sprintf(" %-5s", deltaLabel(entry))
+	switch change, ok := entry.Change(); {
+	case !ok:
+		return lipgloss.NewStyle().Foreground(colorCyan).Render(label)
+	case change > 0:
+		return lipgloss.NewStyle().Foreground(colorGreen).Render(label)
+	case change < 0:
+		return lipgloss.NewStyle().Foreground(colorRed).Render(label)
+	default:
+		return hudDesc.Render(label)
+	}
+}
+
+// sparkline renders daily counts in width columns, summing consecutive days
+// into a column when there are more days than columns.
+func sparkline(daily []int, width int) string {
+	if len(daily) == 0 || width <= 0 {
+		return strings.Repeat(" ", max(width, 0))
+	}
+	cols := daily
+	if len(daily) > width {
+		cols = make([]int, width)
+		for i, n := range daily {
+			cols[i*width/len(daily)] += n
+		}
+	}
+	peak := slices.Max(cols)
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", width-len(cols)))
+	for _, n := range cols {
+		level := 0
+		if peak > 0 && n > 0 {
+			level = (n*(len(sparkTicks)-1) + peak - 1) / peak
+		}
+		b.WriteRune(sparkTicks[level])
+	}
+	return b.String()
+}
+
+// itemKinds labels the kind of a configured item in the unused report.
+var itemKinds = map[usage.RankCategory]string{
+	usage.RankAgents:   "agent",
+	usage.RankSkills:   "skill",
+	usage.RankCommands: "command",
+	usage.RankMCP:      "MCP",
+}
+
+// tildePath abbreviates the home directory prefix of path to "~".
+func tildePath(path, home string) string {
+	if home != "" {
+		if rest, ok := strings.CutPrefix(path, home+string(filepath.Separator)); ok {
+			return filepath.Join("~", rest)
+		}
+	}
+	return path
+}
+
+// configItems lists the agents, skills, custom commands and MCP servers defined by
+// the scanned files. Agents are named after their file and skills after their
+// directory, as Claude Code does by default. Nested commands use ':' as in "/frontend:component".
+func configItems(result *model.ScanResult) []usage.ConfigItem {
+	sep := string(filepath.Separator)
+	commandsMarker := sep + filepath.Join(".claude", "commands") + sep
+	var items []usage.ConfigItem
+	var walk func(files []model.ConfigFile)
+	walk = func(files []model.ConfigFile) {
+		for _, f := range files {
+			walk(f.Children)
+			if f.IsDir {
+				continue
+			}
+			switch f.Category {
+			case model.CategoryMCP:
+				if file, name, ok := strings.Cut(f.Path, "#mcpServers."); ok {
+					items = append(items, usage.ConfigItem{Category: usage.RankMCP, Name: name, Path: file})
+				}
+			case model.CategoryCommands:
+				i := strings.LastIndex(f.Path, commandsMarker)
+				if i < 0 || filepath.Ext(f.Path) != ".md" {
+					continue
+				}
+				rel := strings.TrimSuffix(f.Path[i+len(commandsMarker):], ".md")
+				items = append(items, usage.ConfigItem{Category: usage.RankCommands, Name: strings.ReplaceAll(rel, sep, ":"), Path: f.Path})
+			case model.CategoryAgents:
+				if filepath.Ext(f.Path) == ".md" {
+					items = append(items, usage.ConfigItem{Category: usage.RankAgents, Name: strings.TrimSuffix(filepath.Base(f.Path), ".md"), Path: f.Path})
+				}
+			case model.CategorySkills:
+				if filepath.Base(f.Path) == "SKILL.md" {
+					items = append(items, usage.ConfigItem{Category: usage.RankSkills, Name: filepath.Base(filepath.Dir(f.Path)), Path: f.Path})
+				}
+			}
+		}
+	}
+	walk(result.All())
+	return items
+}
+
+// itemNames returns the sorted, distinct names of the items in category cat.
+func itemNames(items []usage.ConfigItem, cat usage.RankCategory) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, item := range items {
+		if item.Category == cat && !seen[item.Name] {
+			seen[item.Name] = true
+			names = append(names, item.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// customCommandNames returns the slash command names defined by the scanned .claude/commands directories.
+func customCommandNames(result *model.ScanResult) []string {
+	return itemNames(configItems(result), usage.RankCommands)
+}
+
+// formatDuration abbreviates a session length, e.g. "42m" or "1h05m".
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "<1m"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	default:
+		return fmt.Sprintf("%dh%02dm", int(d/time.Hour), int(d%time.Hour/time.Minute))
+	}
+}
+
+// formatTokens abbreviates a token count, e.g. 1234567 → "1.2M".
+func formatTokens(n int64) string {
+	switch {
+	case n >= 1_000_000_000:
+		return fmt.Sprintf("%.1fB", float64(n)/1e9)
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1e3)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
 }
 
 func (r *RankingModel) renderEntry(rank int, entry usage.RankEntry, barWidth int, selected bool) string {
 	gs := gradeStyle(entry.Grade)
+	if r.tab == usage.RankErrors && r.drill != "" {
+		// Error messages need the width of the name and bar columns.
+		line := fmt.Sprintf(" %2d. [%-3s] %5d×  %s ", rank, entry.Grade, entry.Count, entry.Name)
+		if selected {
+			return lipgloss.NewStyle().Bold(true).Foreground(colorYellow).Background(lipgloss.Color("#333333")).Render(line)
+		}
+		return fmt.Sprintf(" %2d. %s %5d×  %s", rank, gs.Render(fmt.Sprintf("[%-3s]", entry.Grade)), entry.Count, entry.Name)
+	}
 
 	rankStr := fmt.Sprintf("%2d.", rank)
 	badge := fmt.Sprintf("[%-3s]", entry.Grade)
 	name := entry.Name
-	if len(name) > 15 {
+	switch {
+	case len(name) <= 15:
+	case r.tab == usage.RankHotspots && (r.hotspots == usage.HotspotsReads || r.hotspots == usage.HotspotsEdits):
+		// The end of a file path tells files apart.
+		name = "…" + name[len(name)-14:]
+	default:
 		name = name[:14] + "…"
 	}
 	namePad := 15 - lipgloss.Width(name)
@@ -295,7 +1030,7 @@ func (r *RankingModel) renderEntry(rank int, entry usage.RankEntry, barWidth int
 		namePad = 0
 	}
 
-	filled := int(entry.LogScore * float64(barWidth))
+	filled := int(entry.Score * float64(barWidth))
 	if filled < 1 && entry.Count > 0 {
 		filled = 1
 	}
@@ -303,18 +1038,19 @@ func (r *RankingModel) renderEntry(rank int, entry usage.RankEntry, barWidth int
 	if empty < 0 {
 		empty = 0
 	}
+	spark := sparkline(entry.Daily, sparkWidth)
 
 	if selected {
 		// Selected item: highlighted with background color.
 		sel := lipgloss.NewStyle().Bold(true).Foreground(colorYellow).Background(lipgloss.Color("#333333"))
 		bar := sel.Render(strings.Repeat("█", filled)) +
 			lipgloss.NewStyle().Foreground(lipgloss.Color("#555555")).Background(lipgloss.Color("#333333")).Render(strings.Repeat("░", empty))
-		return sel.Render(fmt.Sprintf(" %s %s %s%s ", rankStr, badge, name, strings.Repeat(" ", namePad))) +
-			bar + sel.Render(fmt.Sprintf(" %d", entry.Count))
+		return sel.Render(fmt.Sprintf(" %s %s %s%s %s ", rankStr, badge, name, strings.Repeat(" ", namePad), spark)) +
+			bar + sel.Render(" "+r.countLabel(entry)) + r.renderDelta(entry)
 	}
 
 	bar := gs.Render(strings.Repeat("█", filled)) +
 		lipgloss.NewStyle().Foreground(lipgloss.Color("#333333")).Render(strings.Repeat("░", empty))
-	return fmt.Sprintf(" %s %s %s%s %s %d",
-		rankStr, gs.Render(badge), name, strings.Repeat(" ", namePad), bar, entry.Count)
+	return fmt.Sprintf(" %s %s %s%s %s %s %s%s",
+		rankStr, gs.Render(badge), name, strings.Repeat(" ", namePad), gs.Render(spark), bar, r.countLabel(entry), r.renderDelta(entry))
 }
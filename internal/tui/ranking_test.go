@@ -0,0 +1,402 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jeremy-kr/ccfg/internal/model"
+	"github.com/jeremy-kr/ccfg/internal/usage"
+)
+
+// load runs a Load to completion and applies its result.
+func load(t *testing.T, r *RankingModel) {
+	t.Helper()
+	msg, ok := r.Load()().(rankingLoadedMsg)
+	if !ok || !r.Loaded(msg) {
+		t.Fatal("Load did not deliver its result")
+	}
+}
+
+func TestRankingLoadInBackground(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, ".claude", "projects", "-p")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	line := `{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Read","input":{}}]}}` + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "s.jsonl"), []byte(line), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRankingModel(&usage.Collector{HomeDir: home})
+	first := r.Load()
+	if view := r.View(160, 20); !strings.Contains(view, "Loading") {
+		t.Errorf("the view should show a loading state until the data arrives:\n%s", view)
+	}
+	second := r.Load()
+	stale := first().(rankingLoadedMsg)
+	if !errors.Is(stale.err, context.Canceled) {
+		t.Errorf("superseded Load err = %v, want it cancelled", stale.err)
+	}
+	if r.Loaded(stale) {
+		t.Error("the result of a superseded Load should be dropped")
+	}
+	if !r.Loaded(second().(rankingLoadedMsg)) || r.loading || r.data == nil || r.data.Tools[0].Name != "Read" {
+		t.Fatalf("the latest Load should deliver its data, got %+v / %v", r.data, r.err)
+	}
+
+	// Leaving the view cancels a collection in progress.
+	pending := r.Load()
+	r.Cancel()
+	if msg := pending().(rankingLoadedMsg); !errors.Is(msg.err, context.Canceled) {
+		t.Errorf("cancelled Load err = %v, want it cancelled", msg.err)
+	}
+}
+
+func TestFormatTokens(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{999, "999"},
+		{1500, "1.5k"},
+		{2_340_000, "2.3M"},
+		{7_100_000_000, "7.1B"},
+	}
+	for _, tt := range tests {
+		if got := formatTokens(tt.n); got != tt.want {
+			t.Errorf("formatTokens(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestRankingCountLabel(t *testing.T) {
+	r := NewRankingModel(&usage.Collector{})
+	entry := usage.RankEntry{Name: "claude-opus-4", Count: 1_200_000, Cost: 3.456}
+	if got := r.countLabel(entry); got != "1200000" {
+		t.Errorf("invocation label = %q, want %q", got, "1200000")
+	}
+	r.SetTab(usage.RankTokens)
+	if got := r.countLabel(entry); got != "1.2M ≈$3.46" {
+		t.Errorf("token label = %q, want %q", got, "1.2M ≈$3.46")
+	}
+}
+
+func TestRankingMCPDrillDown(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, ".claude", "projects", "-p")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	line := `{"type":"assistant","message":{"content":[{"type":"tool_use","name":"mcp__github__list_prs","input":{}}]}}` + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "s.jsonl"), []byte(line), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRankingModel(&usage.Collector{HomeDir: home, MCPServers: []string{"docs"}})
+	load(t, &r)
+	r.SetTab(usage.RankMCP)
+	if got := r.entries(); len(got) != 2 || got[0].Name != "github" || got[1].Name != "docs" {
+		t.Fatalf("servers = %+v, want github then unused docs", got)
+	}
+
+	r.MoveDown()
+	if r.Drill() {
+		t.Error("an unused server should not open")
+	}
+	r.MoveUp()
+	if !r.Drill() {
+		t.Fatal("Drill on github should open its tools")
+	}
+	if got := r.entries(); len(got) != 1 || got[0].Name != "list_prs" {
+		t.Errorf("github tools = %+v, want list_prs", got)
+	}
+	if !r.Back() || r.drill != "" || r.cursor != 0 {
+		t.Errorf("Back should return to the server list with github selected")
+	}
+	if r.Back() {
+		t.Error("Back without a drill-down should report false")
+	}
+}
+
+func TestRankingSessionsDrillDown(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, ".claude", "projects", "-p")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	ts := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	lines := `{"type":"user","timestamp":"` + ts + `","message":{"content":"hi"}}` + "\n" +
+		`{"type":"assistant","timestamp":"` + ts + `","message":{"id":"m1","content":[]}}` + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "abc.jsonl"), []byte(lines), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRankingModel(&usage.Collector{HomeDir: home})
+	load(t, &r)
+	r.SetTab(usage.RankSessions)
+	if got := r.entries(); len(got) != 1 || got[0].Name != "-p" || r.countLabel(got[0]) != "1 session" {
+		t.Fatalf("projects = %+v, want -p with 1 session", got)
+	}
+	if !r.Drill() {
+		t.Fatal("Drill on a project should list its sessions")
+	}
+	got := r.entries()
+	if len(got) != 1 || got[0].Session == nil || got[0].Session.ID != "abc" {
+		t.Fatalf("sessions = %+v, want abc", got)
+	}
+	if label := r.countLabel(got[0]); label != "2 msgs  <1m  -p" {
+		t.Errorf("session label = %q, want %q", label, "2 msgs  <1m  -p")
+	}
+
+	r.CycleGroup()
+	if r.drill != "" || r.sessions != usage.SessionsByDay {
+		t.Error("CycleGroup on the Sessions tab should leave the drill-down and group by day")
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{30 * time.Second, "<1m"},
+		{42 * time.Minute, "42m"},
+		{65 * time.Minute, "1h05m"},
+	}
+	for _, tt := range tests {
+		if got := formatDuration(tt.d); got != tt.want {
+			t.Errorf("formatDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestConfiguredMCPServers(t *testing.T) {
+	result := &model.ScanResult{
+		User: []model.ConfigFile{{
+			Path:     "/home/me/.claude/settings.json",
+			Category: model.CategorySettings,
+			Children: []model.ConfigFile{{
+				Path:     "/home/me/.claude/settings.json#mcpServers",
+				Category: model.CategoryMCP,
+				Children: []model.ConfigFile{
+					{Path: "/home/me/.claude/settings.json#mcpServers.github", Category: model.CategoryMCP},
+				},
+			}},
+		}},
+		Project: []model.ConfigFile{{
+			Path:     "/p/.mcp.json#mcpServers",
+			Category: model.CategoryMCP,
+			Children: []model.ConfigFile{
+				{Path: "/p/.mcp.json#mcpServers.docs", Category: model.CategoryMCP},
+				{Path: "/p/.mcp.json#mcpServers.github", Category: model.CategoryMCP},
+			},
+		}},
+	}
+	got := configuredMCPServers(result)
+	if len(got) != 2 || got[0] != "docs" || got[1] != "github" {
+		t.Errorf("configuredMCPServers = %v, want [docs github]", got)
+	}
+}
+
+func TestCustomCommandNames(t *testing.T) {
+	dir := filepath.Join(string(filepath.Separator), "p", ".claude", "commands")
+	result := &model.ScanResult{
+		Project: []model.ConfigFile{{
+			Path:     dir,
+			Category: model.CategoryCommands,
+			IsDir:    true,
+			Children: []model.ConfigFile{
+				{Path: filepath.Join(dir, "commit.md"), Category: model.CategoryCommands},
+				{Path: filepath.Join(dir, "notes.txt"), Category: model.CategoryCommands},
+				{Path: filepath.Join(dir, "frontend"), Category: model.CategoryCommands, IsDir: true, Children: []model.ConfigFile{
+					{Path: filepath.Join(dir, "frontend", "component.md"), Category: model.CategoryCommands},
+				}},
+			},
+		}},
+	}
+	got := customCommandNames(result)
+	if len(got) != 2 || got[0] != "commit" || got[1] != "frontend:component" {
+		t.Errorf("customCommandNames = %v, want [commit frontend:component]", got)
+	}
+}
+
+func TestRankingErrorsDrillDown(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, ".claude", "projects", "-p")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	lines := `{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t1","name":"Bash","input":{}}]}}` + "\n" +
+		`{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","is_error":true,"content":"Exit code 2"}]}}` + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "s.jsonl"), []byte(lines), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRankingModel(&usage.Collector{HomeDir: home})
+	load(t, &r)
+	r.SetTab(usage.RankErrors)
+	got := r.entries()
+	if len(got) != 1 || r.countLabel(got[0]) != "1/1  100.0%" {
+		t.Fatalf("errors = %+v, want Bash 1/1", got)
+	}
+	if !r.Drill() {
+		t.Fatal("Drill on Bash should list its error messages")
+	}
+	if got := r.entries(); len(got) != 1 || got[0].Name != "Exit code 2" {
+		t.Errorf("messages = %+v, want Exit code 2", got)
+	}
+	if line := r.renderEntry(1, r.entries()[0], 10, false); !strings.Contains(line, "1×  Exit code 2") {
+		t.Errorf("message row = %q, want the count and full message", line)
+	}
+}
+
+func TestConfigItems(t *testing.T) {
+	claude := filepath.Join(string(filepath.Separator), "u", ".claude")
+	result := &model.ScanResult{
+		User: []model.ConfigFile{
+			{Path: filepath.Join(claude, "agents"), Category: model.CategoryAgents, IsDir: true, Children: []model.ConfigFile{
+				{Path: filepath.Join(claude, "agents", "reviewer.md"), Category: model.CategoryAgents},
+			}},
+			{Path: filepath.Join(claude, "skills"), Category: model.CategorySkills, IsDir: true, Children: []model.ConfigFile{
+				{Path: filepath.Join(claude, "skills", "pdf"), Category: model.CategorySkills, IsDir: true, Children: []model.ConfigFile{
+					{Path: filepath.Join(claude, "skills", "pdf", "SKILL.md"), Category: model.CategorySkills},
+					{Path: filepath.Join(claude, "skills", "pdf", "reference.md"), Category: model.CategorySkills},
+				}},
+			}},
+			{Path: filepath.Join(claude, "settings.json") + "#mcpServers.github", Category: model.CategoryMCP},
+		},
+	}
+	got := configItems(result)
+	want := []usage.ConfigItem{
+		{Category: usage.RankAgents, Name: "reviewer", Path: filepath.Join(claude, "agents", "reviewer.md")},
+		{Category: usage.RankSkills, Name: "pdf", Path: filepath.Join(claude, "skills", "pdf", "SKILL.md")},
+		{Category: usage.RankMCP, Name: "github", Path: filepath.Join(claude, "settings.json")},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("configItems = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("item %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTildePath(t *testing.T) {
+	home := filepath.Join(string(filepath.Separator), "home", "me")
+	if got, want := tildePath(filepath.Join(home, ".claude", "agents", "a.md"), home), filepath.Join("~", ".claude", "agents", "a.md"); got != want {
+		t.Errorf("tildePath = %q, want %q", got, want)
+	}
+	if got := tildePath(home+"x/a.md", home); got != home+"x/a.md" {
+		t.Errorf("tildePath should leave sibling paths alone, got %q", got)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		daily []int
+		width int
+		want  string
+	}{
+		{nil, 4, "    "},
+		{[]int{0, 1, 8}, 4, "  ▁█"},
+		// 8 days summed into 4 columns of two days each.
+		{[]int{1, 1, 0, 0, 2, 2, 4, 4}, 4, "▂ ▄█"},
+	}
+	for _, tt := range tests {
+		if got := sparkline(tt.daily, tt.width); got != tt.want {
+			t.Errorf("sparkline(%v, %d) = %q, want %q", tt.daily, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestDeltaLabel(t *testing.T) {
+	tests := []struct {
+		entry usage.RankEntry
+		want  string
+	}{
+		{usage.RankEntry{Count: 142, Prior: 100}, "+42%"},
+		{usage.RankEntry{Count: 90, Prior: 100}, "-10%"},
+		{usage.RankEntry{Count: 5}, "new"},
+		{usage.RankEntry{}, ""},
+	}
+	for _, tt := range tests {
+		if got := deltaLabel(tt.entry); got != tt.want {
+			t.Errorf("deltaLabel(%d vs %d) = %q, want %q", tt.entry.Count, tt.entry.Prior, got, tt.want)
+		}
+	}
+}
+
+func TestRankingRangePrompt(t *testing.T) {
+	r := NewRankingModel(&usage.Collector{HomeDir: t.TempDir()})
+	r.OpenRangePrompt()
+	r.UpdateRangePrompt(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("soon")})
+	r.UpdateRangePrompt(tea.KeyMsg{Type: tea.KeyEnter})
+	if !r.EditingRange() || r.rangeEdit.err == nil {
+		t.Fatal("an invalid range should keep the prompt open with an error")
+	}
+
+	r.UpdateRangePrompt(tea.KeyMsg{Type: tea.KeyTab})
+	if r.rangeEdit.text != usage.Presets[0] || r.rangeEdit.err != nil {
+		t.Errorf("Tab should offer %q, got %q", usage.Presets[0], r.rangeEdit.text)
+	}
+	cmd := r.UpdateRangePrompt(tea.KeyMsg{Type: tea.KeyEnter})
+	if r.EditingRange() || r.dateRange.IsZero() || cmd == nil {
+		t.Fatal("Enter should apply the preset and reload")
+	}
+
+	r.TogglePeriod()
+	if !r.dateRange.IsZero() || r.period != usage.PeriodAll {
+		t.Errorf("p should clear the range and keep the period, got %v / %v", r.dateRange, r.period)
+	}
+}
+
+func TestRankingCycleGrading(t *testing.T) {
+	r := NewRankingModel(&usage.Collector{HomeDir: t.TempDir()})
+	load(t, &r)
+	gen := r.gen
+	r.CycleGrading()
+	if r.collector.Grading.Model != usage.GradingPercentile || r.data.Grading().Model != usage.GradingPercentile {
+		t.Errorf("G should switch to percentile grading, got %s", r.collector.Grading.Model)
+	}
+	if r.gen != gen {
+		t.Error("regrading should not reload the data")
+	}
+	if view := r.View(160, 20); !strings.Contains(view, "share of entries") {
+		t.Error("the header should explain the active grading")
+	}
+}
+
+func TestRankingHotspots(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, ".claude", "projects", "-work-app")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	line := `{"type":"assistant","message":{"content":[` +
+		`{"type":"tool_use","name":"Bash","input":{"command":"go test ./..."}},` +
+		`{"type":"tool_use","name":"WebFetch","input":{"url":"https://pkg.go.dev/slices"}}]}}`
+	if err := os.WriteFile(filepath.Join(dir, "s.jsonl"), []byte(line+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRankingModel(&usage.Collector{HomeDir: home, ProjectPath: "/work/app"})
+	load(t, &r)
+	r.SetTab(usage.RankHotspots)
+	if view := r.View(160, 20); !strings.Contains(view, "Bash(go test:*)") {
+		t.Errorf("the Bash hotspots should suggest an allow rule for the selected row:\n%s", view)
+	}
+	for range 3 {
+		r.CycleGroup()
+	}
+	if r.hotspots != usage.HotspotsDomains || !strings.Contains(r.View(160, 20), "WebFetch(domain:pkg.go.dev)") {
+		t.Errorf("g should cycle to the Domains hotspots, got %s", r.hotspots)
+	}
+}
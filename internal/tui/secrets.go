@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jeremy-kr/ccfg/internal/model"
+)
+
+var (
+	secretStyle        = lipgloss.NewStyle().Bold(true).Foreground(colorMagenta)
+	secretTrackedStyle = lipgloss.NewStyle().Bold(true).Foreground(colorRed)
+)
+
+// secretBadge returns a secret count for a tree node, or "" if none were found.
+// Files tracked by git get an extra warning since the secret may already be committed.
+func secretBadge(f *model.ConfigFile) string {
+	if len(f.Secrets) == 0 {
+		return ""
+	}
+	if f.GitTracked {
+		return " " + secretTrackedStyle.Render(fmt.Sprintf("🔑%d git!", len(f.Secrets)))
+	}
+	return " " + secretStyle.Render(fmt.Sprintf("🔑%d", len(f.Secrets)))
+}
+
+// renderSecrets renders secret findings as a block shown above the file content.
+func renderSecrets(f *model.ConfigFile) string {
+	if len(f.Secrets) == 0 {
+		return ""
+	}
+
+	rows := make([][]string, 0, len(f.Secrets))
+	for _, s := range f.Secrets {
+		rows = append(rows, []string{fmt.Sprintf("L%d", s.Line), s.Rule, s.Masked})
+	}
+
+	var b strings.Builder
+	b.WriteString(secretStyle.Render(fmt.Sprintf("🔑 Possible secrets (%d)", len(f.Secrets))))
+	b.WriteString("\n")
+	if f.GitTracked {
+		b.WriteString(secretTrackedStyle.Render("⚠ This file is tracked by git — rotate these credentials and purge them from history."))
+		b.WriteString("\n")
+	}
+	b.WriteString(renderTable([]string{"LINE", "RULE", "VALUE"}, rows))
+	b.WriteString(tableRuleStyle.Render(strings.Repeat("━", 40)))
+	b.WriteString("\n")
+	return b.String()
+}
@@ -60,6 +60,14 @@ var (
 	dirStyle = lipgloss.NewStyle().
 			Foreground(colorOrange)
 
+	// Recently changed node marker and change notification style.
+	changedStyle = lipgloss.NewStyle().Bold(true).Foreground(colorMagenta)
+
+	// Unified diff line styles.
+	diffAddStyle  = lipgloss.NewStyle().Foreground(colorGreen)
+	diffDelStyle  = lipgloss.NewStyle().Foreground(colorRed)
+	diffHunkStyle = lipgloss.NewStyle().Foreground(colorCyan)
+
 	// HUD element styles.
 	hudLabelNav = lipgloss.NewStyle().Bold(true).Foreground(colorGreen)
 	hudLabelCmd = lipgloss.NewStyle().Bold(true).Foreground(colorCyan)
@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	tableHeaderStyle = lipgloss.NewStyle().Bold(true).Foreground(colorCyan)
+	tableRuleStyle   = lipgloss.NewStyle().Foreground(colorDimGray)
+)
+
+// renderTable renders rows as left-aligned columns under a bold header.
+// The last column is not padded so long values (commands, URLs) are only cut by the panel width.
+func renderTable(headers []string, rows [][]string) string {
+	widths := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = lipgloss.Width(h)
+	}
+	for _, row := range rows {
+		for i := 0; i < len(row) && i < len(widths); i++ {
+			if w := lipgloss.Width(row[i]); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(tableHeaderStyle.Render(joinCells(headers, widths)))
+	b.WriteString("\n")
+
+	ruleW := 0
+	for _, w := range widths {
+		ruleW += w + 2
+	}
+	b.WriteString(tableRuleStyle.Render(strings.Repeat("─", max(ruleW-2, 1))))
+	b.WriteString("\n")
+
+	for _, row := range rows {
+		b.WriteString(joinCells(row, widths))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// joinCells pads each cell to its column width and joins them with two spaces.
+func joinCells(cells []string, widths []int) string {
+	var b strings.Builder
+	for i, cell := range cells {
+		if i >= len(widths) {
+			break
+		}
+		b.WriteString(cell)
+		if i < len(cells)-1 {
+			b.WriteString(strings.Repeat(" ", widths[i]-lipgloss.Width(cell)+2))
+		}
+	}
+	return b.String()
+}
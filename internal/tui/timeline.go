@@ -0,0 +1,171 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jeremy-kr/ccfg/internal/merger"
+	"github.com/jeremy-kr/ccfg/internal/timeline"
+)
+
+// timelineKindMarks maps each edit kind to its list marker.
+var timelineKindMarks = map[timeline.Kind]string{
+	timeline.KindCreated:  "+",
+	timeline.KindModified: "~",
+	timeline.KindRemoved:  "-",
+}
+
+// TimelineModel manages the state of the change timeline view.
+type TimelineModel struct {
+	timeline *timeline.Timeline
+	entries  []timeline.Entry // Snapshot of the timeline, newest first.
+	cursor   int              // Selected entry.
+	offset   int              // List scroll offset.
+	scroll   int              // Detail scroll offset.
+	height   int              // Number of visible rows.
+}
+
+// NewTimelineModel creates a TimelineModel over the given timeline.
+func NewTimelineModel(t *timeline.Timeline) TimelineModel {
+	return TimelineModel{timeline: t}
+}
+
+// Load refreshes the entry list and selects the newest entry.
+func (v *TimelineModel) Load() {
+	v.entries = v.timeline.Entries()
+	v.cursor = 0
+	v.offset = 0
+	v.scroll = 0
+}
+
+// SetHeight sets the number of visible rows.
+func (v *TimelineModel) SetHeight(h int) {
+	v.height = h
+}
+
+// MoveUp selects the newer entry.
+func (v *TimelineModel) MoveUp() {
+	if v.cursor > 0 {
+		v.cursor--
+		v.scroll = 0
+		v.adjustScroll()
+	}
+}
+
+// MoveDown selects the older entry.
+func (v *TimelineModel) MoveDown() {
+	if v.cursor < len(v.entries)-1 {
+		v.cursor++
+		v.scroll = 0
+		v.adjustScroll()
+	}
+}
+
+func (v *TimelineModel) adjustScroll() {
+	if v.height <= 0 {
+		return
+	}
+	if v.cursor < v.offset {
+		v.offset = v.cursor
+	}
+	if v.cursor >= v.offset+v.height {
+		v.offset = v.cursor - v.height + 1
+	}
+}
+
+// ScrollDetail scrolls the diff pane by n lines (negative scrolls up).
+func (v *TimelineModel) ScrollDetail(n int) {
+	if len(v.entries) == 0 {
+		return
+	}
+	lines := len(detailLines(v.entries[v.cursor]))
+	v.scroll = min(max(v.scroll+n, 0), max(lines-v.height, 0))
+}
+
+// View renders the entry list and the selected entry's diff side by side.
+func (v *TimelineModel) View(width, height int) string {
+	if len(v.entries) == 0 {
+		return lipgloss.NewStyle().Foreground(colorDimGray).Render("  No changes recorded in this session")
+	}
+
+	listW := max(width*35/100, 24)
+	detailW := max(width-listW-3, 10)
+
+	list := v.renderList(listW, height)
+	detail := v.renderDetail(v.entries[v.cursor], detailW, height)
+	sep := lipgloss.NewStyle().Foreground(colorDimGray).Render(strings.TrimSuffix(strings.Repeat(" │\n", height), "\n"))
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, list, sep, " ", detail)
+}
+
+func (v *TimelineModel) renderList(width, height int) string {
+	end := min(v.offset+height, len(v.entries))
+	lines := make([]string, 0, height)
+	for i := v.offset; i < end; i++ {
+		e := v.entries[i]
+		text := fmt.Sprintf("%s %s %s", e.Time.Format("15:04:05"), timelineKindMarks[e.Kind], filepath.Base(e.Path))
+		style := lipgloss.NewStyle()
+		if i == v.cursor {
+			style = treeSelectedStyle.Background(lipgloss.Color("#333333"))
+		}
+		lines = append(lines, style.MaxWidth(width).Render(text))
+	}
+	return lipgloss.NewStyle().Width(width).Render(strings.Join(lines, "\n"))
+}
+
+func (v *TimelineModel) renderDetail(e timeline.Entry, width, height int) string {
+	lines := detailLines(e)
+	end := min(v.scroll+height, len(lines))
+	return lipgloss.NewStyle().Width(width).MaxWidth(width).Render(strings.Join(lines[min(v.scroll, end):end], "\n"))
+}
+
+// detailLines renders an entry's header, effective setting changes and colored diff.
+func detailLines(e timeline.Entry) []string {
+	lines := []string{
+		hudKey.Render(e.Path),
+		hudDesc.Render(fmt.Sprintf("%s at %s", e.Kind, e.Time.Format("2006-01-02 15:04:05"))),
+		"",
+	}
+
+	if len(e.Settings) > 0 {
+		lines = append(lines, dirStyle.Render("Effective settings changed:"))
+		for _, c := range e.Settings {
+			lines = append(lines, "  "+renderValueChange(c))
+		}
+		lines = append(lines, "")
+	}
+
+	for _, l := range strings.Split(strings.TrimSuffix(e.Diff, "\n"), "\n") {
+		lines = append(lines, renderDiffLine(l))
+	}
+	return lines
+}
+
+// renderValueChange formats a merged setting change as "key: old → new".
+func renderValueChange(c merger.ValueChange) string {
+	switch {
+	case c.Old == nil:
+		return diffAddStyle.Render(fmt.Sprintf("+ %s = %v [%s]", c.Key, c.New.Value, c.New.Scope))
+	case c.New == nil:
+		return diffDelStyle.Render(fmt.Sprintf("- %s = %v [%s]", c.Key, c.Old.Value, c.Old.Scope))
+	default:
+		return fmt.Sprintf("~ %s: %v [%s] → %v [%s]", c.Key, c.Old.Value, c.Old.Scope, c.New.Value, c.New.Scope)
+	}
+}
+
+// renderDiffLine colors a unified diff line.
+func renderDiffLine(l string) string {
+	switch {
+	case strings.HasPrefix(l, "+++"), strings.HasPrefix(l, "---"):
+		return hudDesc.Render(l)
+	case strings.HasPrefix(l, "@@"):
+		return diffHunkStyle.Render(l)
+	case strings.HasPrefix(l, "+"):
+		return diffAddStyle.Render(l)
+	case strings.HasPrefix(l, "-"):
+		return diffDelStyle.Render(l)
+	}
+	return l
+}
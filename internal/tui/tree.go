@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -47,6 +48,8 @@ type TreeModel struct {
 	offset int        // Scroll offset.
 	height int        // Number of visible rows.
 	filter string     // Search filter (empty string means no filter).
+
+	changed map[string]bool // Paths of recently changed nodes (highlighted).
 }
 
 // NewTreeModel builds a tree from a ScanResult.
@@ -306,6 +309,14 @@ func (t *TreeModel) View(width int, focused bool) string {
 }
 
 func (t *TreeModel) renderNode(node TreeNode, selected, focused bool) string {
+	line := t.renderNodeLine(node, selected, focused)
+	if node.File != nil && t.changed[node.File.Path] {
+		line += changedStyle.Render(" ✎")
+	}
+	return line
+}
+
+func (t *TreeModel) renderNodeLine(node TreeNode, selected, focused bool) string {
 	if node.File == nil {
 		// Scope header.
 		arrow := "▶"
@@ -346,9 +357,9 @@ func (t *TreeModel) renderNode(node TreeNode, selected, focused bool) string {
 		count := fmt.Sprintf("(%d)", len(node.Children))
 		text := fmt.Sprintf("%s%s %s%s %s", indent, arrow, emoji, node.Label, count)
 		if selected && focused {
-			return treeSelectedStyle.Render(text)
+			return treeSelectedStyle.Render(text) + fileBadges(node.File)
 		}
-		return dirStyle.Render(text)
+		return dirStyle.Render(text) + fileBadges(node.File)
 	}
 
 	// Virtual leaf node (individual item from a JSON internal section).
@@ -363,7 +374,7 @@ func (t *TreeModel) renderNode(node TreeNode, selected, focused bool) string {
 	// File node.
 	if selected && focused {
 		text := fmt.Sprintf("%s▸ %s%s", indent, emoji, node.Label)
-		return treeSelectedStyle.Render(text)
+		return treeSelectedStyle.Render(text) + fileBadges(node.File)
 	}
 
 	status := fileMissingStyle.Render("○")
@@ -371,7 +382,7 @@ func (t *TreeModel) renderNode(node TreeNode, selected, focused bool) string {
 		status = fileExistsStyle.Render("●")
 	}
 	text := fmt.Sprintf("%s%s %s%s", indent, status, emoji, node.Label)
-	return treeItemStyle.Render(text)
+	return treeItemStyle.Render(text) + fileBadges(node.File)
 }
 
 // nodeDepth returns the depth of the given file node in the tree.
@@ -466,3 +477,84 @@ func restoreExpanded(nodes []TreeNode, expanded map[string]bool) {
 		restoreExpanded(nodes[i].Children, expanded)
 	}
 }
+
+// UpdateFiles replaces the top-level nodes for the given paths with the matching
+// entries from result, keeping the expansion state and cursor position.
+// Falls back to a full rebuild when the set of scopes differs.
+func (t *TreeModel) UpdateFiles(result *model.ScanResult, updated []string) {
+	state := t.CaptureState()
+
+	fresh := NewTreeModel(result)
+	if !sameScopes(fresh.roots, t.roots) {
+		t.roots = fresh.roots
+		t.RestoreState(state)
+		return
+	}
+
+	dirty := make(map[string]bool, len(updated))
+	for _, p := range updated {
+		dirty[p] = true
+	}
+	for i := range t.roots {
+		if len(fresh.roots[i].Children) != len(t.roots[i].Children) {
+			t.roots[i].Children = fresh.roots[i].Children
+			continue
+		}
+		for j, node := range fresh.roots[i].Children {
+			if node.File != nil && dirty[node.File.Path] {
+				t.roots[i].Children[j] = node
+			}
+		}
+	}
+	t.RestoreState(state)
+}
+
+// sameScopes reports whether both trees have the same scope headers in the same order.
+func sameScopes(a, b []TreeNode) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Label != b[i].Label {
+			return false
+		}
+	}
+	return true
+}
+
+// Highlight marks the nodes affected by the changed paths. Each path marks the
+// deepest node that is the path itself or contains it (the parent, for removed files).
+func (t *TreeModel) Highlight(paths []string) {
+	t.changed = make(map[string]bool)
+	for _, p := range paths {
+		for _, root := range t.roots {
+			if n := deepestNode(root.Children, p); n != "" {
+				t.changed[n] = true
+				break
+			}
+		}
+	}
+}
+
+// ClearHighlight removes all change highlights.
+func (t *TreeModel) ClearHighlight() { t.changed = nil }
+
+// deepestNode returns the path of the deepest node that equals or contains path.
+func deepestNode(nodes []TreeNode, path string) string {
+	for _, node := range nodes {
+		if node.File == nil || node.File.IsVirtual {
+			continue
+		}
+		np := node.File.Path
+		if np == path {
+			return np
+		}
+		if strings.HasPrefix(path, np+string(filepath.Separator)) {
+			if d := deepestNode(node.Children, path); d != "" {
+				return d
+			}
+			return np
+		}
+	}
+	return ""
+}
@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/jeremy-kr/ccfg/internal/model"
+)
+
+func testScan(skillNames ...string) *model.ScanResult {
+	skills := model.ConfigFile{
+		Path:     "/p/.claude/skills",
+		Category: model.CategorySkills,
+		Exists:   true,
+		IsDir:    true,
+	}
+	for _, n := range skillNames {
+		skills.Children = append(skills.Children, model.ConfigFile{
+			Path:        "/p/.claude/skills/" + n,
+			Category:    model.CategorySkills,
+			Exists:      true,
+			Description: n,
+		})
+	}
+	return &model.ScanResult{
+		Project: []model.ConfigFile{
+			{Path: "/p/CLAUDE.md", Category: model.CategoryInstructions, Exists: true},
+			skills,
+		},
+	}
+}
+
+func TestTreeUpdateFilesKeepsState(t *testing.T) {
+	tree := NewTreeModel(testScan("a.md"))
+	toggleByPath(tree.roots, "/p/.claude/skills")
+	tree.MoveDown() // CLAUDE.md
+
+	tree.UpdateFiles(testScan("a.md", "b.md"), []string{"/p/.claude/skills"})
+
+	skills := tree.roots[0].Children[1]
+	if !skills.Expanded {
+		t.Error("skills node should stay expanded")
+	}
+	if len(skills.Children) != 2 {
+		t.Errorf("skills children = %d, want 2", len(skills.Children))
+	}
+	if f := tree.SelectedFile(); f == nil || f.Path != "/p/CLAUDE.md" {
+		t.Errorf("selection = %+v, want CLAUDE.md", f)
+	}
+}
+
+func TestTreeHighlight(t *testing.T) {
+	tree := NewTreeModel(testScan("a.md"))
+	tree.Highlight([]string{"/p/.claude/skills/a.md", "/p/.claude/skills/gone.md"})
+
+	if !tree.changed["/p/.claude/skills/a.md"] {
+		t.Error("changed file should be highlighted")
+	}
+	if !tree.changed["/p/.claude/skills"] {
+		t.Error("removed file should highlight its parent directory")
+	}
+
+	tree.ClearHighlight()
+	if len(tree.changed) != 0 {
+		t.Error("highlights should be cleared")
+	}
+}
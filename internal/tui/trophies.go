@@ -0,0 +1,118 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jeremy-kr/ccfg/internal/usage"
+)
+
+// trophyHeaderRows is the number of rows above the achievement list (summary + separator).
+const trophyHeaderRows = 2
+
+// trophyBarWidth is the number of columns of a locked achievement's progress bar.
+const trophyBarWidth = 12
+
+// TrophyModel manages the state of the trophy screen.
+type TrophyModel struct {
+	achievements []usage.Achievement
+	cursor       int
+	offset       int
+	height       int // Number of visible rows.
+}
+
+// SetAchievements replaces the listed achievements, keeping the selection.
+func (v *TrophyModel) SetAchievements(a []usage.Achievement) {
+	v.achievements = a
+	v.cursor = min(v.cursor, max(len(a)-1, 0))
+	v.adjustScroll()
+}
+
+// SetHeight sets the number of visible rows.
+func (v *TrophyModel) SetHeight(h int) {
+	v.height = h
+	v.adjustScroll()
+}
+
+// MoveUp selects the previous achievement.
+func (v *TrophyModel) MoveUp() {
+	if v.cursor > 0 {
+		v.cursor--
+		v.adjustScroll()
+	}
+}
+
+// MoveDown selects the next achievement.
+func (v *TrophyModel) MoveDown() {
+	if v.cursor < len(v.achievements)-1 {
+		v.cursor++
+		v.adjustScroll()
+	}
+}
+
+func (v *TrophyModel) adjustScroll() {
+	if v.height <= 0 {
+		return
+	}
+	if v.cursor < v.offset {
+		v.offset = v.cursor
+	}
+	if v.cursor >= v.offset+v.height {
+		v.offset = v.cursor - v.height + 1
+	}
+}
+
+// View renders the unlocked count and one row per achievement.
+func (v *TrophyModel) View(width, height int) string {
+	if len(v.achievements) == 0 {
+		return lipgloss.NewStyle().Foreground(colorDimGray).Render("  No usage data yet")
+	}
+	unlocked := 0
+	for _, a := range v.achievements {
+		if a.IsUnlocked() {
+			unlocked++
+		}
+	}
+	summary := lipgloss.NewStyle().Bold(true).Foreground(colorYellow).Render(
+		fmt.Sprintf("🏆 %d / %d unlocked", unlocked, len(v.achievements)),
+	) + hudDesc.Render("  (all-time usage in the current scope)")
+	sep := lipgloss.NewStyle().Foreground(colorDimGray).Render(strings.Repeat("─", max(width-4, 0)))
+
+	lines := []string{summary, sep}
+	end := min(v.offset+max(height-trophyHeaderRows, 0), len(v.achievements))
+	for i := v.offset; i < end; i++ {
+		lines = append(lines, v.renderRow(v.achievements[i], i == v.cursor, width))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (v *TrophyModel) renderRow(a usage.Achievement, selected bool, width int) string {
+	title := fmt.Sprintf("%-14s", a.Title)
+	desc := fmt.Sprintf("%-42s", a.Description)
+
+	var icon, status string
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(colorYellow)
+	switch {
+	case a.IsUnlocked():
+		icon = "🏆"
+		status = lipgloss.NewStyle().Foreground(colorGreen).Render("unlocked " + a.Unlocked.Local().Format("2006-01-02"))
+	case a.Goal == 0:
+		icon = "🔒"
+		titleStyle = lipgloss.NewStyle().Foreground(colorDimGray)
+		status = hudDesc.Render("nothing configured")
+	default:
+		icon = "🔒"
+		titleStyle = lipgloss.NewStyle().Foreground(colorDimGray)
+		filled := a.Progress * trophyBarWidth / a.Goal
+		status = lipgloss.NewStyle().Foreground(colorCyan).Render(strings.Repeat("█", filled)) +
+			lipgloss.NewStyle().Foreground(colorDimGray).Render(strings.Repeat("░", trophyBarWidth-filled)) +
+			hudDesc.Render(fmt.Sprintf(" %d/%d", a.Progress, a.Goal))
+	}
+
+	row := icon + " " + titleStyle.Render(title) + " " + hudDesc.Render(desc) + " " + status
+	if selected {
+		row = lipgloss.NewStyle().Background(lipgloss.Color("#333333")).Render(row)
+	}
+	return lipgloss.NewStyle().MaxWidth(width).Render(row)
+}
@@ -0,0 +1,33 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremy-kr/ccfg/internal/usage"
+)
+
+func TestTrophyView(t *testing.T) {
+	var v TrophyModel
+	v.SetHeight(2)
+	v.SetAchievements([]usage.Achievement{
+		{Title: "Hello, World", Description: "Make your first tool call", Goal: 1, Progress: 1, Unlocked: time.Date(2026, 9, 28, 10, 0, 0, 0, time.Local)},
+		{Title: "Shell Jockey", Description: "Make 1,000 Bash calls", Goal: 1000, Progress: 250},
+		{Title: "Summoner", Description: "Delegate to one of your custom agents"},
+	})
+	view := v.View(200, 2+trophyHeaderRows)
+	for _, want := range []string{"1 / 3 unlocked", "unlocked 2026-09-28", "250/1000"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "Summoner") {
+		t.Error("rows beyond the height should be scrolled out")
+	}
+	v.MoveDown()
+	v.MoveDown()
+	if view = v.View(200, 2+trophyHeaderRows); !strings.Contains(view, "nothing configured") || strings.Contains(view, "Hello, World") {
+		t.Errorf("moving down should scroll to the last row:\n%s", view)
+	}
+}
@@ -0,0 +1,168 @@
+package usage
+
+import "time"
+
+// Achievement is a usage milestone of the player profile.
+type Achievement struct {
+	ID          string
+	Title       string
+	Description string
+	Goal        int       // Progress needed to unlock; 0 if the configuration offers nothing to earn it with
+	Progress    int       // Progress so far, at most Goal
+	Unlocked    time.Time // Start of the index bucket whose usage reached Goal; zero while locked
+}
+
+// IsUnlocked reports whether the achievement has been earned.
+func (a Achievement) IsUnlocked() bool {
+	return !a.Unlocked.IsZero()
+}
+
+// milestone defines an achievement by its goal and how progress is measured.
+type milestone struct {
+	id, title, desc string
+	goal            func(cfg configured) int
+	progress        func(s *playerStats) int
+}
+
+// milestones lists every achievement in display order.
+var milestones = []milestone{
+	{"first-call", "Hello, World", "Make your first tool call", fixedGoal(1), func(s *playerStats) int { return s.toolCalls }},
+	{"custom-agent", "Summoner", "Delegate to one of your custom agents", anyConfiguredGoal(RankAgents), usedConfigured(RankAgents)},
+	{"custom-command", "Incantation", "Run one of your custom slash commands", anyConfiguredGoal(RankCommands), usedConfigured(RankCommands)},
+	{"all-skills", "Completionist", "Use every configured skill at least once", configuredGoal(RankSkills), usedConfigured(RankSkills)},
+	{"mcp-servers", "Networker", "Call tools on 3 different MCP servers", fixedGoal(3), func(s *playerStats) int { return len(s.mcpServers) }},
+	{"bash-1000", "Shell Jockey", "Make 1,000 Bash calls", fixedGoal(1000), func(s *playerStats) int { return s.tools["Bash"] }},
+	{"tools-10000", "Power User", "Make 10,000 tool calls", fixedGoal(10000), func(s *playerStats) int { return s.toolCalls }},
+	{"streak-7", "On a Roll", "Call a tool or send a message 7 days in a row", fixedGoal(7), func(s *playerStats) int { return s.bestStreak }},
+	{"streak-30", "Habit Formed", "Call a tool or send a message 30 days in a row", fixedGoal(30), func(s *playerStats) int { return s.bestStreak }},
+}
+
+func fixedGoal(n int) func(configured) int {
+	return func(configured) int { return n }
+}
+
+// anyConfiguredGoal requires one configured item of cat, if there are any.
+func anyConfiguredGoal(cat RankCategory) func(configured) int {
+	return func(cfg configured) int { return min(len(cfg[cat]), 1) }
+}
+
+// configuredGoal requires every configured item of cat.
+func configuredGoal(cat RankCategory) func(configured) int {
+	return func(cfg configured) int { return len(cfg[cat]) }
+}
+
+// usedConfigured measures how many configured items of cat have been used.
+func usedConfigured(cat RankCategory) func(*playerStats) int {
+	return func(s *playerStats) int { return len(s.used[cat]) }
+}
+
+// configured holds the names of configured items per category.
+type configured map[RankCategory]map[string]bool
+
+// playerStats accumulates all-time usage toward milestones.
+type playerStats struct {
+	tools      map[string]int                   // Calls per tool
+	toolCalls  int                              // Calls of any tool
+	used       map[RankCategory]map[string]bool // Configured items used at least once
+	mcpServers map[string]bool                  // MCP servers with at least one call
+	lastDay    time.Time                        // Local day of the latest activity
+	streak     int                              // Consecutive active days up to lastDay
+	bestStreak int
+}
+
+// achievementTracker replays usage in time order and records when each milestone was reached.
+type achievementTracker struct {
+	config   configured
+	stats    playerStats
+	unlocked []time.Time // Per milestone
+}
+
+// newAchievementTracker creates a tracker for the configured items.
+func newAchievementTracker(items []ConfigItem) *achievementTracker {
+	t := &achievementTracker{
+		config: make(configured),
+		stats: playerStats{
+			tools:      make(map[string]int),
+			used:       make(map[RankCategory]map[string]bool),
+			mcpServers: make(map[string]bool),
+		},
+		unlocked: make([]time.Time, len(milestones)),
+	}
+	for _, item := range items {
+		if t.config[item.Category] == nil {
+			t.config[item.Category] = make(map[string]bool)
+		}
+		t.config[item.Category][item.Name] = true
+	}
+	return t
+}
+
+// add counts usage at t, which must not precede earlier calls, and unlocks the
+// milestones it completes.
+func (t *achievementTracker) add(at time.Time, c Counts) {
+	s := &t.stats
+	for name, n := range normalizeCounts(c.Tools) {
+		s.tools[name] += n
+		s.toolCalls += n
+		if server, _, ok := splitMCPTool(name); ok {
+			s.mcpServers[server] = true
+		}
+	}
+	for cat, counts := range map[RankCategory]map[string]int{RankAgents: normalizeCounts(c.Agents), RankSkills: normalizeCounts(c.Skills), RankCommands: c.Commands} {
+		for name := range counts {
+			if !t.config[cat][name] {
+				continue
+			}
+			if s.used[cat] == nil {
+				s.used[cat] = make(map[string]bool)
+			}
+			s.used[cat][name] = true
+		}
+	}
+	if len(c.Tools) > 0 || c.messageCount() > 0 {
+		s.noteDay(at)
+	}
+
+	for i, m := range milestones {
+		if goal := m.goal(t.config); t.unlocked[i].IsZero() && goal > 0 && m.progress(s) >= goal {
+			t.unlocked[i] = at
+		}
+	}
+}
+
+// noteDay extends the streak of consecutive active local days with the day of t.
+func (s *playerStats) noteDay(t time.Time) {
+	day := startOfDay(t)
+	switch {
+	case s.lastDay.IsZero():
+		s.streak = 1
+	case day.Equal(s.lastDay):
+		return
+	case day.Equal(startOfDay(s.lastDay.AddDate(0, 0, 1))):
+		s.streak++
+	default:
+		s.streak = 1
+	}
+	s.lastDay = day
+	s.bestStreak = max(s.bestStreak, s.streak)
+}
+
+// achievements returns every milestone with its progress, in display order.
+func (t *achievementTracker) achievements() []Achievement {
+	if t == nil {
+		return nil
+	}
+	out := make([]Achievement, len(milestones))
+	for i, m := range milestones {
+		goal := m.goal(t.config)
+		out[i] = Achievement{
+			ID:          m.id,
+			Title:       m.title,
+			Description: m.desc,
+			Goal:        goal,
+			Progress:    min(m.progress(&t.stats), goal),
+			Unlocked:    t.unlocked[i],
+		}
+	}
+	return out
+}
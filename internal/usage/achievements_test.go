@@ -0,0 +1,128 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCollect_Achievements(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, ".claude", "projects", "-project-a")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	day1 := startOfDay(time.Now()).AddDate(0, 0, -20).Add(10 * time.Hour)
+	line := func(at time.Time, blocks string) string {
+		return `{"type":"assistant","timestamp":"` + at.UTC().Format(time.RFC3339) + `","message":{"content":[` + blocks + `]}}`
+	}
+	bash := `{"type":"tool_use","name":"Bash","input":{}}`
+	var lines []string
+	// Active on days 1, 3-9 and 11: the 7-day streak completes on day 9.
+	for _, d := range []int{0, 2, 3, 4, 5, 6, 7, 8, 10} {
+		lines = append(lines, line(day1.AddDate(0, 0, d), bash))
+	}
+	lines = append(lines,
+		line(day1.AddDate(0, 0, 4).Add(time.Hour), `{"type":"tool_use","name":"Task","input":{"subagent_type":"reviewer"}}`),
+		line(day1.AddDate(0, 0, 5), `{"type":"tool_use","name":"Skill","input":{"skill":"commit"}}`),
+	)
+	writeJSONL(t, filepath.Join(dir, "s.jsonl"), lines)
+
+	c := &Collector{HomeDir: home, Period: PeriodDay, Config: []ConfigItem{
+		{Category: RankAgents, Name: "reviewer"},
+		{Category: RankSkills, Name: "commit"},
+		{Category: RankSkills, Name: "deploy"},
+	}}
+	data, err := c.Collect(ScopeAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]Achievement)
+	for _, a := range data.Achievements {
+		got[a.ID] = a
+	}
+	if len(got) != len(milestones) {
+		t.Fatalf("Achievements = %d, want every milestone", len(got))
+	}
+
+	bucket := func(at time.Time) time.Time { return at.UTC().Truncate(bucketSize) }
+	tests := []struct {
+		id       string
+		unlocked time.Time
+		progress int
+	}{
+		{"first-call", bucket(day1), 1},
+		{"custom-agent", bucket(day1.AddDate(0, 0, 4).Add(time.Hour)), 1},
+		{"streak-7", bucket(day1.AddDate(0, 0, 8)), 7},
+		{"all-skills", time.Time{}, 1},
+		{"custom-command", time.Time{}, 0},
+		{"bash-1000", time.Time{}, 9},
+	}
+	for _, tt := range tests {
+		a := got[tt.id]
+		if !a.Unlocked.Equal(tt.unlocked) || a.Progress != tt.progress {
+			t.Errorf("%s: unlocked %v progress %d/%d, want %v progress %d", tt.id, a.Unlocked, a.Progress, a.Goal, tt.unlocked, tt.progress)
+		}
+	}
+	if got["all-skills"].Goal != 2 || got["custom-command"].Goal != 0 {
+		t.Errorf("goals = %d/%d, want 2 configured skills and none for commands", got["all-skills"].Goal, got["custom-command"].Goal)
+	}
+
+	// Live usage unlocks at the time it is seen.
+	before := time.Now()
+	data.Add(Counts{Skills: map[string]int{"deploy": 1}})
+	for _, a := range data.Achievements {
+		if a.ID == "all-skills" && (a.Unlocked.Before(before) || a.Progress != 2) {
+			t.Errorf("all-skills after live use = %+v, want unlocked now", a)
+		}
+	}
+}
+
+func TestCollect_AchievementsIgnoreScope(t *testing.T) {
+	home := t.TempDir()
+	day := startOfDay(time.Now()).AddDate(0, 0, -3).Add(10 * time.Hour)
+	for i, project := range []string{"a", "b"} {
+		dir := filepath.Join(home, ".claude", "projects", "-project-"+project)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		at := day.AddDate(0, 0, i).UTC().Format(time.RFC3339)
+		writeJSONL(t, filepath.Join(dir, "s.jsonl"), []string{
+			`{"type":"assistant","cwd":"/project/` + project + `","timestamp":"` + at + `","message":{"content":[{"type":"tool_use","name":"Bash","input":{}}]}}`,
+		})
+	}
+
+	c := &Collector{HomeDir: home, ProjectPath: "/project/b"}
+	unlocked := func(scope DataScope) map[string]time.Time {
+		data, err := c.Collect(scope)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make(map[string]time.Time)
+		for _, a := range data.Achievements {
+			got[a.ID] = a.Unlocked
+		}
+		return got
+	}
+	all, project := unlocked(ScopeAll), unlocked(ScopeProject)
+	if !all["first-call"].Equal(day.UTC().Truncate(bucketSize)) {
+		t.Errorf("first-call unlocked %v, want the first call in project a", all["first-call"])
+	}
+	for id, at := range all {
+		if !project[id].Equal(at) {
+			t.Errorf("%s: unlocked %v in project scope, want %v as in all projects", id, project[id], at)
+		}
+	}
+}
+
+func TestPlayerStats_Streak(t *testing.T) {
+	var s playerStats
+	start := time.Date(2026, 3, 1, 23, 0, 0, 0, time.Local)
+	for _, d := range []int{0, 0, 1, 2, 4, 5} {
+		s.noteDay(start.AddDate(0, 0, d))
+	}
+	if s.streak != 2 || s.bestStreak != 3 {
+		t.Errorf("streak/best = %d/%d, want 2/3", s.streak, s.bestStreak)
+	}
+}
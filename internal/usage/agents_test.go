@@ -1,6 +1,7 @@
 package usage
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -23,18 +24,18 @@ func TestCollectAgents_Opencode(t *testing.T) {
 	}
 	writeJSONL(t, filepath.Join(dir, "test.jsonl"), lines)
 
-	counts, err := collectAgents(home, "", time.Time{})
+	counts, err := collectCounts(context.Background(), newIndex(), home, ProjectFilter{}, time.Time{})
 	if err != nil {
 		t.Fatal(err)
 	}
 	// explore is a built-in infrastructure agent and should be excluded
-	if counts["explore"] != 0 {
-		t.Errorf("explore: got %d, want 0 (excluded)", counts["explore"])
+	if counts.Agents["explore"] != 0 {
+		t.Errorf("explore: got %d, want 0 (excluded)", counts.Agents["explore"])
 	}
-	if counts["librarian"] != 1 {
-		t.Errorf("librarian: got %d, want 1", counts["librarian"])
+	if counts.Agents["librarian"] != 1 {
+		t.Errorf("librarian: got %d, want 1", counts.Agents["librarian"])
 	}
-	if counts["Read"] != 0 {
+	if counts.Agents["Read"] != 0 {
 		t.Errorf("Read should not be counted as agent")
 	}
 }
@@ -56,16 +57,16 @@ func TestCollectAgents_ClaudeCode(t *testing.T) {
 	writeJSONL(t, filepath.Join(dir, "session.jsonl"), lines)
 
 	// Verify that projects/ subdirectories are scanned in All scope
-	counts, err := collectAgents(home, "", time.Time{})
+	counts, err := collectCounts(context.Background(), newIndex(), home, ProjectFilter{}, time.Time{})
 	if err != nil {
 		t.Fatal(err)
 	}
 	// explore is a built-in infrastructure agent and should be excluded
-	if counts["explore"] != 0 {
-		t.Errorf("explore: got %d, want 0 (excluded)", counts["explore"])
+	if counts.Agents["explore"] != 0 {
+		t.Errorf("explore: got %d, want 0 (excluded)", counts.Agents["explore"])
 	}
-	if counts["code-reviewer"] != 1 {
-		t.Errorf("code-reviewer: got %d, want 1", counts["code-reviewer"])
+	if counts.Agents["code-reviewer"] != 1 {
+		t.Errorf("code-reviewer: got %d, want 1", counts.Agents["code-reviewer"])
 	}
 }
 
@@ -88,18 +89,18 @@ func TestCollectAgents_GeneralPurposeResolve(t *testing.T) {
 	}
 	writeJSONL(t, filepath.Join(dir, "session.jsonl"), lines)
 
-	counts, err := collectAgents(home, "", time.Time{})
+	counts, err := collectCounts(context.Background(), newIndex(), home, ProjectFilter{}, time.Time{})
 	if err != nil {
 		t.Fatal(err)
 	}
-	if counts["horner"] != 2 {
-		t.Errorf("horner: got %d, want 2", counts["horner"])
+	if counts.Agents["horner"] != 2 {
+		t.Errorf("horner: got %d, want 2", counts.Agents["horner"])
 	}
-	if counts["newey"] != 1 {
-		t.Errorf("newey: got %d, want 1", counts["newey"])
+	if counts.Agents["newey"] != 1 {
+		t.Errorf("newey: got %d, want 1", counts.Agents["newey"])
 	}
-	if counts["general-purpose"] != 2 {
-		t.Errorf("general-purpose: got %d, want 2", counts["general-purpose"])
+	if counts.Agents["general-purpose"] != 2 {
+		t.Errorf("general-purpose: got %d, want 2", counts.Agents["general-purpose"])
 	}
 }
 
@@ -115,12 +116,12 @@ func TestCollectAgents_ProjectScope(t *testing.T) {
 	}
 	writeJSONL(t, filepath.Join(projDir, "session.jsonl"), lines)
 
-	counts, err := collectAgents(home, "/project/foo", time.Time{})
+	counts, err := collectCounts(context.Background(), newIndex(), home, ProjectFilter{Path: "/project/foo"}, time.Time{})
 	if err != nil {
 		t.Fatal(err)
 	}
-	if counts["code-reviewer"] != 1 {
-		t.Errorf("code-reviewer: got %d, want 1", counts["code-reviewer"])
+	if counts.Agents["code-reviewer"] != 1 {
+		t.Errorf("code-reviewer: got %d, want 1", counts.Agents["code-reviewer"])
 	}
 }
 
@@ -139,17 +140,17 @@ func TestCollectSkills_Opencode(t *testing.T) {
 	}
 	writeJSONL(t, filepath.Join(dir, "test.jsonl"), lines)
 
-	counts, err := collectSkills(home, "", time.Time{})
+	counts, err := collectCounts(context.Background(), newIndex(), home, ProjectFilter{}, time.Time{})
 	if err != nil {
 		t.Fatal(err)
 	}
-	if counts["git-master"] != 2 {
-		t.Errorf("git-master: got %d, want 2", counts["git-master"])
+	if counts.Skills["git-master"] != 2 {
+		t.Errorf("git-master: got %d, want 2", counts.Skills["git-master"])
 	}
-	if counts["commit"] != 1 {
-		t.Errorf("commit: got %d, want 1", counts["commit"])
+	if counts.Skills["commit"] != 1 {
+		t.Errorf("commit: got %d, want 1", counts.Skills["commit"])
 	}
-	if counts["explore"] != 0 {
+	if counts.Skills["explore"] != 0 {
 		t.Errorf("explore should not be counted as skill")
 	}
 }
@@ -168,14 +169,14 @@ func TestCollectSkills_ClaudeCode(t *testing.T) {
 	}
 	writeJSONL(t, filepath.Join(dir, "session.jsonl"), lines)
 
-	counts, err := collectSkills(home, "", time.Time{})
+	counts, err := collectCounts(context.Background(), newIndex(), home, ProjectFilter{}, time.Time{})
 	if err != nil {
 		t.Fatal(err)
 	}
-	if counts["commit"] != 2 {
-		t.Errorf("commit: got %d, want 2", counts["commit"])
+	if counts.Skills["commit"] != 2 {
+		t.Errorf("commit: got %d, want 2", counts.Skills["commit"])
 	}
-	if counts["explore"] != 0 {
+	if counts.Skills["explore"] != 0 {
 		t.Errorf("explore should not be counted as skill")
 	}
 }
@@ -195,15 +196,15 @@ func TestCollectTools_ClaudeCode(t *testing.T) {
 	}
 	writeJSONL(t, filepath.Join(dir, "session.jsonl"), lines)
 
-	counts, err := collectTools(home, "", time.Time{})
+	counts, err := collectCounts(context.Background(), newIndex(), home, ProjectFilter{}, time.Time{})
 	if err != nil {
 		t.Fatal(err)
 	}
-	if counts["Read"] != 2 {
-		t.Errorf("Read: got %d, want 2", counts["Read"])
+	if counts.Tools["Read"] != 2 {
+		t.Errorf("Read: got %d, want 2", counts.Tools["Read"])
 	}
-	if counts["Bash"] != 1 {
-		t.Errorf("Bash: got %d, want 1", counts["Bash"])
+	if counts.Tools["Bash"] != 1 {
+		t.Errorf("Bash: got %d, want 1", counts.Tools["Bash"])
 	}
 }
 
@@ -214,6 +215,8 @@ func TestEncodeProjectPath(t *testing.T) {
 	}{
 		{"/Users/jeremy/code/ccfg", "-Users-jeremy-code-ccfg"},
 		{"/project/foo", "-project-foo"},
+		{"/home/me/my.app", "-home-me-my-app"},
+		{"/home/me/my_app v2", "-home-me-my-app-v2"},
 	}
 	for _, tt := range tests {
 		got := encodeProjectPath(tt.input)
@@ -223,20 +226,6 @@ func TestEncodeProjectPath(t *testing.T) {
 	}
 }
 
-func TestTranscriptDirs_AllScope(t *testing.T) {
-	home := t.TempDir()
-
-	// Create both transcripts/ and projects/
-	os.MkdirAll(filepath.Join(home, ".claude", "transcripts"), 0o755)
-	os.MkdirAll(filepath.Join(home, ".claude", "projects", "-proj-a"), 0o755)
-	os.MkdirAll(filepath.Join(home, ".claude", "projects", "-proj-b"), 0o755)
-
-	dirs := transcriptDirs(home, "")
-	if len(dirs) != 3 {
-		t.Errorf("expected 3 dirs (transcripts + 2 projects), got %d: %v", len(dirs), dirs)
-	}
-}
-
 func TestCollectTools_SessionMeta(t *testing.T) {
 	home := t.TempDir()
 	dir := filepath.Join(home, ".claude", "usage-data", "session-meta")
@@ -249,12 +238,12 @@ func TestCollectTools_SessionMeta(t *testing.T) {
 		ToolCounts:  map[string]int{"Read": 10, "Bash": 5},
 	})
 
-	counts, err := collectTools(home, "/project/a", time.Time{})
+	counts, err := collectCounts(context.Background(), newIndex(), home, ProjectFilter{Path: "/project/a"}, time.Time{})
 	if err != nil {
 		t.Fatal(err)
 	}
-	if counts["Read"] != 10 {
-		t.Errorf("Read: got %d, want 10", counts["Read"])
+	if counts.Tools["Read"] != 10 {
+		t.Errorf("Read: got %d, want 10", counts.Tools["Read"])
 	}
 }
 
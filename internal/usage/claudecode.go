@@ -0,0 +1,138 @@
+package usage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ClaudeCodeSource reads Claude Code transcripts: one JSONL file per session under
+// ~/.claude/projects/<encoded project path>/.
+//
+// Format: {"type":"assistant","cwd":"...","timestamp":"...","message":{"id":"...","model":"...",
+// "content":[{"type":"tool_use","id":"...","name":"Task","input":{...}}],"usage":{...}}}
+type ClaudeCodeSource struct{}
+
+// claudeCodeLine parses a Claude Code transcript line.
+type claudeCodeLine struct {
+	Type        string `json:"type"`
+	Cwd         string `json:"cwd"`
+	IsMeta      bool   `json:"isMeta"`
+	IsSidechain bool   `json:"isSidechain"`
+	Message     struct {
+		ID      string          `json:"id"`
+		Model   string          `json:"model"`
+		Content json.RawMessage `json:"content"`
+		Usage   struct {
+			InputTokens              int64 `json:"input_tokens"`
+			OutputTokens             int64 `json:"output_tokens"`
+			CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+			CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+// contentBlock represents an element in Claude Code's content array.
+type contentBlock struct {
+	Type      string          `json:"type"`
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Input     json.RawMessage `json:"input"`
+	ToolUseID string          `json:"tool_use_id"`
+	IsError   bool            `json:"is_error"`
+	Content   json.RawMessage `json:"content"`
+}
+
+// Files lists the session transcripts of all projects, or of the sessions matching
+// project. A directory belongs to the working directory its transcripts record;
+// until one does, it is matched by its encoded name.
+func (ClaudeCodeSource) Files(homeDir string, project ProjectFilter) []string {
+	projectsBase := filepath.Join(homeDir, ".claude", "projects")
+	entries, err := os.ReadDir(projectsBase)
+	if err != nil {
+		return nil
+	}
+	var dirs []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(projectsBase, entry.Name())
+		if project.Path != "" {
+			if cwd := dirCwd(dir); cwd != "" && !project.Matches(cwd) {
+				continue
+			} else if cwd == "" && entry.Name() != encodeProjectPath(project.Path) {
+				continue
+			}
+		}
+		dirs = append(dirs, dir)
+	}
+	return jsonlFiles(dirs...)
+}
+
+// Decode parses a Claude Code line. Tool calls are tool_use blocks of the message
+// content and results are tool_result blocks; slash commands are recorded as a
+// <command-name> tag in the user message.
+func (ClaudeCodeSource) Decode(line []byte) (Line, bool) {
+	var cl claudeCodeLine
+	if err := json.Unmarshal(line, &cl); err != nil {
+		return Line{}, false
+	}
+	l := Line{
+		Cwd:       cl.Cwd,
+		Meta:      cl.IsMeta,
+		Sidechain: cl.IsSidechain,
+	}
+	l.Timestamp, _ = extractTimestamp(line)
+	switch cl.Type {
+	case "user":
+		l.Role = cl.Type
+		if name, ok := commandFromTag(messageText(cl.Message.Content)); ok {
+			l.Command = name
+		}
+	case "assistant":
+		l.Role = cl.Type
+		l.MessageID = cl.Message.ID
+		l.Model = cl.Message.Model
+		l.Usage = TokenUsage{
+			Input:         cl.Message.Usage.InputTokens,
+			Output:        cl.Message.Usage.OutputTokens,
+			CacheRead:     cl.Message.Usage.CacheReadInputTokens,
+			CacheCreation: cl.Message.Usage.CacheCreationInputTokens,
+		}
+	}
+
+	var blocks []contentBlock
+	if err := json.Unmarshal(cl.Message.Content, &blocks); err != nil {
+		return l, true // String content or none.
+	}
+	for _, b := range blocks {
+		switch b.Type {
+		case "tool_use":
+			if b.Name != "" {
+				l.Calls = append(l.Calls, ToolCall{ID: b.ID, Name: b.Name, Input: b.Input})
+			}
+		case "tool_result":
+			r := ToolResult{CallID: b.ToolUseID, IsError: b.IsError}
+			if b.IsError {
+				r.Text = messageText(b.Content)
+			}
+			l.Results = append(l.Results, r)
+		}
+	}
+	return l, true
+}
+
+// encodeProjectPath converts a project path to Claude's directory encoding format,
+// which replaces every character other than an ASCII letter or digit with '-'.
+// Example: /Users/jeremy/code/my.app → -Users-jeremy-code-my-app
+func encodeProjectPath(path string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '-'
+	}, path)
+}
@@ -0,0 +1,67 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestClaudeCodeSource_Decode(t *testing.T) {
+	lines, counts := extractFixture(t, ClaudeCodeSource{}, "claudecode.jsonl")
+	if len(lines) != 10 {
+		t.Fatalf("decoded %d lines, want 10 (invalid JSON skipped)", len(lines))
+	}
+	if want := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC); !lines[0].Timestamp.Equal(want) || lines[0].Cwd != "/home/me/code/ccfg" {
+		t.Errorf("first line = %+v, want its timestamp and cwd", lines[0])
+	}
+	if l := lines[1]; l.Role != "user" || l.Command != "commit" {
+		t.Errorf("command line = %+v, want a user message invoking commit", l)
+	}
+	if l := lines[6]; len(l.Results) != 1 || !l.Results[0].IsError || l.Results[0].CallID != "toolu_2" {
+		t.Errorf("error result line = %+v, want a failed toolu_2", l)
+	}
+	if l := lines[7]; len(l.Results) != 1 || l.Results[0].Text != "" {
+		t.Errorf("successful results should not carry their output, got %+v", l.Results)
+	}
+
+	if counts.Agents["reviewer"] != 1 || counts.Skills["commit"] != 1 || counts.Commands["commit"] != 1 {
+		t.Errorf("agents/skills/commands = %v %v %v, want reviewer, commit and /commit once", counts.Agents, counts.Skills, counts.Commands)
+	}
+	if counts.Tools["Task"] != 1 || counts.Tools["Bash"] != 1 || counts.Tools["Skill"] != 1 || len(counts.Tools) != 3 {
+		t.Errorf("tools = %v, want Task, Bash and Skill once", counts.Tools)
+	}
+	// msg_1 spans two lines but counts once.
+	if got := counts.Tokens["claude-sonnet-4-5-20250929"].Total(); got != 2480 {
+		t.Errorf("sonnet tokens = %d, want 2480", got)
+	}
+	if got := counts.AgentTokens["reviewer"]["claude-haiku-4-5"].Total(); got != 320 {
+		t.Errorf("reviewer tokens = %d, want 320 from its sidechain", got)
+	}
+	if counts.Messages["user"] != 1 || counts.Messages["assistant"] != 2 {
+		t.Errorf("messages = %v, want 1 prompt and 2 replies", counts.Messages)
+	}
+	if counts.Errors["Bash"] != 1 || counts.ErrorSnippets["Bash"]["Exit code 128"] != 1 {
+		t.Errorf("errors = %v %v, want one Bash failure", counts.Errors, counts.ErrorSnippets)
+	}
+}
+
+func TestClaudeCodeSource_Files(t *testing.T) {
+	home := t.TempDir()
+	for _, project := range []string{"-home-me-a", "-home-me-b"} {
+		dir := filepath.Join(home, ".claude", "projects", project)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		writeJSONL(t, filepath.Join(dir, "s.jsonl"), nil)
+		writeJSONL(t, filepath.Join(dir, "notes.txt"), nil)
+	}
+
+	if got := (ClaudeCodeSource{}).Files(home, ProjectFilter{}); len(got) != 2 {
+		t.Errorf("Files(all) = %v, want both sessions", got)
+	}
+	want := filepath.Join(home, ".claude", "projects", "-home-me-b", "s.jsonl")
+	if got := (ClaudeCodeSource{}).Files(home, ProjectFilter{Path: "/home/me/b"}); len(got) != 1 || got[0] != want {
+		t.Errorf("Files(/home/me/b) = %v, want [%s]", got, want)
+	}
+}
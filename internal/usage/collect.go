@@ -0,0 +1,216 @@
+package usage
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// maxWorkers caps the number of transcript files parsed concurrently.
+const maxWorkers = 8
+
+// window is a half-open time range [since, until). Zero bounds are open.
+type window struct {
+	since, until time.Time
+}
+
+// collectWindows brings idx up to date and tallies agents, tools, skills, commands
+// and tokens from all transcripts for each window. Files are parsed on a bounded
+// worker pool, and only bytes appended since idx last saw a file are read. Tool
+// counts also include session-meta totals. Returns ctx.Err() if the context is cancelled.
+func collectWindows(ctx context.Context, idx *usageIndex, ts transcripts, windows ...window) ([]Counts, error) {
+	earliest := windows[0].since
+	for _, w := range windows {
+		if w.since.Before(earliest) {
+			earliest = w.since
+		}
+	}
+	files := ts.files(earliest)
+
+	pending, err := updateIndex(ctx, idx, files)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Counts, len(windows))
+	for i, w := range windows {
+		total := newCounts()
+		for _, f := range files {
+			if entry := idx.Files[f.path]; entry != nil {
+				entry.sumRange(w, f.path, total)
+			}
+		}
+		for path, partial := range pending {
+			partial.sumRange(w, path, total)
+		}
+		if err := collectToolsFromSessionMeta(ts.homeDir, ts.project, w, total.Tools); err != nil {
+			return nil, err
+		}
+		out[i] = total
+	}
+	return out, nil
+}
+
+// updateIndex parses what is new in files into idx on a bounded worker pool.
+// It returns the unterminated last line of each file, which is counted but not
+// indexed since it may still be growing.
+func updateIndex(ctx context.Context, idx *usageIndex, files []transcriptFile) (map[string]*fileIndex, error) {
+	type job struct {
+		file  transcriptFile
+		entry *fileIndex
+	}
+	jobs := make(chan job)
+	var (
+		mu      sync.Mutex
+		pending = make(map[string]*fileIndex)
+		wg      sync.WaitGroup
+	)
+	workers := min(runtime.GOMAXPROCS(0), maxWorkers, max(len(files), 1))
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				// Lines parsed before a read error still count.
+				entry, partial, _ := updateFileIndex(ctx, j.file, j.entry)
+				mu.Lock()
+				if entry != nil {
+					idx.Files[j.file.path] = entry
+				}
+				pending[j.file.path] = partial
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, f := range files {
+		mu.Lock()
+		entry := idx.Files[f.path]
+		mu.Unlock()
+		select {
+		case jobs <- job{f, entry}:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// extractLine adds the usage found on a decoded transcript line: agent, skill, command
+// and tool invocations, token usage, conversation messages, failed tool calls and
+// the commands, files and domains tools were called with.
+// state carries context between lines of the same file.
+func extractLine(line Line, state *lineState, counts Counts) {
+	if state.Cwd == "" {
+		state.Cwd = line.Cwd
+	}
+	if name, ok := extractAgent(line); ok {
+		counts.Agents[name]++
+	}
+	if name, ok := extractSkill(line); ok {
+		counts.Skills[name]++
+	}
+	if line.Command != "" {
+		counts.Commands[line.Command]++
+	}
+	for _, call := range line.Calls {
+		counts.Tools[call.Name]++
+	}
+	extractTokens(line, state, counts)
+	extractMessage(line, state, counts)
+	extractToolResults(line, state, counts)
+	extractHotspots(line, counts)
+}
+
+// merged returns c after adding other's counts into it.
+func (c Counts) merged(other Counts) Counts {
+	c.merge(other)
+	return c
+}
+
+// merge adds other's counts into c.
+func (c Counts) merge(other Counts) {
+	for name, n := range other.Agents {
+		c.Agents[name] += n
+	}
+	for name, n := range other.Tools {
+		c.Tools[name] += n
+	}
+	for name, n := range other.Skills {
+		c.Skills[name] += n
+	}
+	for name, n := range other.Commands {
+		c.Commands[name] += n
+	}
+	for prefix, n := range other.BashCommands {
+		c.BashCommands[prefix] += n
+	}
+	for path, n := range other.FileReads {
+		c.FileReads[path] += n
+	}
+	for path, n := range other.FileEdits {
+		c.FileEdits[path] += n
+	}
+	for domain, n := range other.WebDomains {
+		c.WebDomains[domain] += n
+	}
+	for role, n := range other.Messages {
+		c.Messages[role] += n
+	}
+	for name, n := range other.Errors {
+		c.Errors[name] += n
+	}
+	for name, snippets := range other.ErrorSnippets {
+		if c.ErrorSnippets[name] == nil {
+			c.ErrorSnippets[name] = make(map[string]int, len(snippets))
+		}
+		for snippet, n := range snippets {
+			c.ErrorSnippets[name][snippet] += n
+		}
+	}
+	c.Tokens.merge(other.Tokens)
+	mergeGrouped(c.AgentTokens, other.AgentTokens)
+	mergeGrouped(c.ProjectTokens, other.ProjectTokens)
+	mergeGrouped(c.DayTokens, other.DayTokens)
+	for day, counts := range other.Daily {
+		c.day(day).merge(counts)
+	}
+	for _, s := range other.Sessions {
+		c.addSession(s)
+	}
+}
+
+// day returns the counts for a local day, allocating them on first use.
+func (c Counts) day(key string) Counts {
+	d, ok := c.Daily[key]
+	if !ok {
+		d = newCounts()
+		c.Daily[key] = d
+	}
+	return d
+}
+
+// addLine adds the counts extracted from a single line, attributing them to
+// project and, if known, to the local day.
+func (c Counts) addLine(line Counts, project, day string) {
+	c.merge(line)
+	c.addTokenDims(line.Tokens, project, day)
+	if day != "" {
+		d := c.day(day)
+		d.merge(line)
+		d.addTokenDims(line.Tokens, project, day)
+	}
+}
+
+// contains reports whether t falls within the window.
+func (w window) contains(t time.Time) bool {
+	return (w.since.IsZero() || !t.Before(w.since)) && (w.until.IsZero() || t.Before(w.until))
+}
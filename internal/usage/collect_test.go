@@ -0,0 +1,72 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// collectCounts tallies usage from the default sources at or after cutoff (zero = all time). See collectWindows.
+func collectCounts(ctx context.Context, idx *usageIndex, homeDir string, project ProjectFilter, cutoff time.Time) (Counts, error) {
+	counts, err := collectWindows(ctx, idx, transcripts{DefaultSources, homeDir, project}, window{since: cutoff})
+	if err != nil {
+		return Counts{}, err
+	}
+	return counts[0], nil
+}
+
+func TestCollectCounts_SinglePassAcrossFiles(t *testing.T) {
+	home := t.TempDir()
+	for p := 0; p < 3; p++ {
+		dir := filepath.Join(home, ".claude", "projects", fmt.Sprintf("-project-%d", p))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		for f := 0; f < 5; f++ {
+			writeJSONL(t, filepath.Join(dir, fmt.Sprintf("s%d.jsonl", f)), []string{
+				// One line carrying an agent, a skill and two tools.
+				`{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Task","input":{"subagent_type":"reviewer"}},{"type":"tool_use","name":"Skill","input":{"skill":"commit"}}]}}`,
+				`{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Read","input":{}}]}}`,
+				`{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Skill","input":{"skill":"git-master"}}]}}`,
+			})
+		}
+	}
+
+	counts, err := collectCounts(context.Background(), newIndex(), home, ProjectFilter{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 15 files: the per-category counts must add up exactly across workers.
+	// The Task/Skill line yields only its first match per category, as before.
+	if counts.Agents["reviewer"] != 15 {
+		t.Errorf("reviewer = %d, want 15", counts.Agents["reviewer"])
+	}
+	if counts.Skills["commit"] != 15 || counts.Skills["git-master"] != 15 {
+		t.Errorf("skills = %v, want commit and git-master 15 each", counts.Skills)
+	}
+	if counts.Tools["Task"] != 15 || counts.Tools["Skill"] != 30 || counts.Tools["Read"] != 15 {
+		t.Errorf("tools = %v, want Task and Read 15 each and Skill 30", counts.Tools)
+	}
+}
+
+func TestCollectCounts_Cancelled(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, ".claude", "transcripts")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeJSONL(t, filepath.Join(dir, "s.jsonl"), []string{
+		`{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Read","input":{}}]}}`,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := collectCounts(ctx, newIndex(), home, ProjectFilter{}, time.Time{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
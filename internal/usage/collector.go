@@ -1,46 +1,216 @@
 package usage
 
 import (
+	"context"
 	"fmt"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 )
 
 // Collector collects Claude Code usage data.
 type Collector struct {
-	HomeDir     string     // User home directory
-	ProjectPath string     // Current project path (empty string disables project filtering)
-	Period      TimePeriod // Time period filter
+	HomeDir     string             // User home directory
+	ProjectPath string             // Current project path (empty string disables project filtering)
+	Period      TimePeriod         // Time period filter
+	Range       DateRange          // Explicit date range; overrides Period when non-zero
+	CacheDir    string             // Directory for the persistent usage index (empty string disables it)
+	Prices      PriceTable         // Prices for cost estimates (nil uses DefaultPrices)
+	Grading     Grading            // How rankings are graded (zero grades on the log scale)
+	Sources     []TranscriptSource // Transcript formats to read (nil reads DefaultSources)
+	MCPServers  []string           // Configured MCP server names, listed in the MCP ranking even without calls
+	Commands    []string           // Custom command names defined under .claude/commands (e.g. "frontend:component")
+	Config      []ConfigItem       // Configured agents, skills, commands and MCP servers for the unused report
 }
 
 // Collect gathers usage data for the given scope and assigns grades.
 func (c *Collector) Collect(scope DataScope) (*UsageData, error) {
-	projectFilter := ""
-	if scope == ScopeProject && c.ProjectPath != "" {
-		projectFilter = c.ProjectPath
+	return c.CollectContext(context.Background(), scope)
+}
+
+// CollectContext is like Collect but stops early when ctx is cancelled.
+func (c *Collector) CollectContext(ctx context.Context, scope DataScope) (*UsageData, error) {
+	ts := c.transcripts(scope)
+	idx := newIndex()
+	if c.CacheDir != "" {
+		idx = loadIndex(filepath.Join(c.CacheDir, indexFileName))
+	}
+	now := time.Now()
+	w := c.window()
+	windows := []window{w}
+	if !w.since.IsZero() {
+		end := now
+		if !w.until.IsZero() && w.until.Before(now) {
+			end = w.until
+		}
+		windows = append(windows, priorWindow(w.since, end))
+	}
+	counts, err := collectWindows(ctx, idx, ts, windows...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect usage: %w", err)
+	}
+	history, err := collectHistory(ctx, idx, ts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect usage: %w", err)
+	}
+	// Achievements are earned across every project, whatever the scope.
+	all := history
+	if scope != ScopeAll {
+		if all, err = collectHistory(ctx, idx, c.transcripts(ScopeAll)); err != nil {
+			return nil, fmt.Errorf("failed to collect usage: %w", err)
+		}
+	}
+	trophies := newAchievementTracker(c.Config)
+	for _, b := range all {
+		trophies.add(b.at, b.counts)
+	}
+	if c.CacheDir != "" {
+		// A stale or missing index only costs a reparse next time.
+		_ = idx.save(filepath.Join(c.CacheDir, indexFileName))
+	}
+
+	prices := c.Prices
+	if prices == nil {
+		prices = DefaultPrices
+	}
+	for i := range counts {
+		counts[i].normalize()
+	}
+	data := &UsageData{
+		counts:         counts[0],
+		since:          w.since,
+		until:          w.until,
+		prices:         prices,
+		grading:        c.Grading,
+		projectPath:    c.ProjectPath,
+		mcpServers:     c.MCPServers,
+		customCommands: c.Commands,
+		config:         c.Config,
+		lastUsed:       lastUsedIn(history),
+		trophies:       trophies,
+	}
+	if len(counts) > 1 {
+		data.prior, data.HasPrior = counts[1], true
 	}
+	data.rank()
+	return data, nil
+}
 
-	cutoff := c.Period.Cutoff()
+// Tail returns a Tailer over the transcripts Collect reads for scope, starting at their current end.
+// It returns nil when the range has already ended, since no new usage can fall within it.
+func (c *Collector) Tail(scope DataScope) *Tailer {
+	w := c.window()
+	if !w.until.IsZero() && !w.until.After(time.Now()) {
+		return nil
+	}
+	return newTailer(c.transcripts(scope), w.since)
+}
 
-	toolCounts, err := collectTools(c.HomeDir, projectFilter, cutoff)
-	if err != nil {
-		return nil, fmt.Errorf("failed to collect tools: %w", err)
+// transcripts selects the transcripts of c's sources for scope.
+func (c *Collector) transcripts(scope DataScope) transcripts {
+	ts := transcripts{sources: c.Sources, homeDir: c.HomeDir}
+	if ts.sources == nil {
+		ts.sources = DefaultSources
+	}
+	if scope != ScopeAll {
+		ts.project = ProjectFilter{Path: c.ProjectPath, Nested: scope == ScopeProjectTree}
 	}
+	return ts
+}
 
-	agentCounts, err := collectAgents(c.HomeDir, projectFilter, cutoff)
-	if err != nil {
-		return nil, fmt.Errorf("failed to collect agents: %w", err)
+// window returns the time range collected: Range if set, otherwise Period up to now.
+func (c *Collector) window() window {
+	if !c.Range.IsZero() {
+		return window{since: c.Range.Since, until: c.Range.Until}
 	}
+	return window{since: c.Period.Cutoff()}
+}
 
-	skillCounts, err := collectSkills(c.HomeDir, projectFilter, cutoff)
-	if err != nil {
-		return nil, fmt.Errorf("failed to collect skills: %w", err)
+// rank rebuilds the ranking lists and their trends from the raw counts.
+func (d *UsageData) rank() {
+	d.Agents = d.rankTrend(agentCounts)
+	d.Tools = d.rankTrend(builtinToolCounts)
+	d.Skills = d.rankTrend(skillCounts)
+	d.Commands = d.rankTrend(commandCounts)
+	for i := range d.Commands {
+		d.Commands[i].Custom = slices.Contains(d.customCommands, d.Commands[i].Name)
 	}
+	d.MCP = d.rankTrend(d.mcpServerCounts)
+	_, _, perServer := splitMCP(d.counts.Tools, nil)
+	d.MCPTools = make(map[string][]RankEntry, len(perServer))
+	for server := range perServer {
+		d.MCPTools[server] = d.rankTrend(mcpToolCounts(server))
+	}
+
+	d.Tokens = make(map[TokenGroup][]RankEntry, tokenGroupCount)
+	for g := range TokenGroup(tokenGroupCount) {
+		entries := d.rankTrend(tokenCounts(g))
+		groups := tokenGroups(d.counts, g)
+		for i := range entries {
+			entries[i].Cost = d.prices.Cost(groups[entries[i].Name])
+		}
+		d.Tokens[g] = entries
+	}
+	d.TokenTotal = d.counts.Tokens.Total()
+	d.CostTotal = d.prices.Cost(d.counts.Tokens)
+	d.Hotspots = make(map[HotspotGroup][]RankEntry, hotspotGroupCount)
+	for g := range HotspotGroup(hotspotGroupCount) {
+		d.Hotspots[g] = d.rankTrend(d.hotspotCounts(g))
+	}
+	d.rankSessions()
+	d.rankErrors()
+	d.Unused = d.rankUnused()
+	d.Achievements = d.trophies.achievements()
+}
+
+// Grading returns how the entries are graded.
+func (d *UsageData) Grading() Grading {
+	return d.grading
+}
+
+// SetGrading re-grades every ranking under g. Regrading is not reported as promotions.
+func (d *UsageData) SetGrading(g Grading) {
+	d.grading = g
+	d.rank()
+}
+
+// Add merges newly observed usage into the data, re-ranks every category and
+// returns the entries whose grade improved.
+func (d *UsageData) Add(delta Counts) []Promotion {
+	if delta.Empty() {
+		return nil
+	}
+	before := map[RankCategory][]RankEntry{RankAgents: d.Agents, RankTools: d.Tools, RankSkills: d.Skills, RankMCP: d.MCP, RankCommands: d.Commands}
+
+	if d.counts.Agents == nil {
+		d.counts = newCounts()
+	}
+	delta.normalize()
+	d.counts.merge(delta)
+	if d.lastUsed == nil {
+		d.lastUsed = make(lastUse)
+	}
+	d.lastUsed.noteCounts(delta, time.Now())
+	if d.trophies != nil {
+		d.trophies.add(time.Now(), delta)
+	}
+	d.rank()
 
-	return &UsageData{
-		Tools:  Rank(normalizeCounts(toolCounts)),
-		Agents: Rank(normalizeCounts(agentCounts)),
-		Skills: Rank(normalizeCounts(skillCounts)),
-	}, nil
+	after := map[RankCategory][]RankEntry{RankAgents: d.Agents, RankTools: d.Tools, RankSkills: d.Skills, RankMCP: d.MCP, RankCommands: d.Commands}
+	var promoted []Promotion
+	for _, cat := range []RankCategory{RankAgents, RankTools, RankSkills, RankMCP, RankCommands} {
+		old := make(map[string]Grade, len(before[cat]))
+		for _, e := range before[cat] {
+			old[e.Name] = e.Grade
+		}
+		for _, e := range after[cat] {
+			if g, ok := old[e.Name]; ok && e.Grade < g {
+				promoted = append(promoted, Promotion{Category: cat, Name: e.Name, From: g, To: e.Grade})
+			}
+		}
+	}
+	return promoted
 }
 
 // normalizeMap maps opencode lowercase names to Claude Code PascalCase names.
@@ -64,6 +234,13 @@ var normalizeMap = map[string]string{
 	"plan":    "Plan",
 }
 
+// normalize merges case variants of agent, tool and skill names in place.
+func (c *Counts) normalize() {
+	c.Agents = normalizeCounts(c.Agents)
+	c.Tools = normalizeCounts(c.Tools)
+	c.Skills = normalizeCounts(c.Skills)
+}
+
 // normalizeCounts merges case variants of the same tool or agent into a single canonical name.
 func normalizeCounts(counts map[string]int) map[string]int {
 	if len(counts) == 0 {
@@ -72,13 +249,18 @@ func normalizeCounts(counts map[string]int) map[string]int {
 
 	result := make(map[string]int, len(counts))
 	for name, count := range counts {
-		canonical := name
-		if mapped, ok := normalizeMap[name]; ok {
-			canonical = mapped
-		} else if mapped, ok := normalizeMap[strings.ToLower(name)]; ok {
-			canonical = mapped
-		}
-		result[canonical] += count
+		result[canonicalName(name)] += count
 	}
 	return result
 }
+
+// canonicalName returns the Claude Code name of a tool or agent.
+func canonicalName(name string) string {
+	if mapped, ok := normalizeMap[name]; ok {
+		return mapped
+	}
+	if mapped, ok := normalizeMap[strings.ToLower(name)]; ok {
+		return mapped
+	}
+	return name
+}
@@ -0,0 +1,51 @@
+package usage
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// commandNamePattern matches the command tag Claude Code records for slash commands,
+// e.g. "<command-name>/commit</command-name>".
+var commandNamePattern = regexp.MustCompile(`<command-name>/?([^<\s]+)</command-name>`)
+
+// typedCommandPattern matches a message that starts with a typed slash command, e.g. "/commit fix typo".
+var typedCommandPattern = regexp.MustCompile(`^/([A-Za-z][\w:.-]*)(?:\s|$)`)
+
+// commandFromTag returns the slash command named by a <command-name> tag in text.
+func commandFromTag(text string) (name string, ok bool) {
+	if !strings.Contains(text, "command-name") {
+		return "", false
+	}
+	if m := commandNamePattern.FindStringSubmatch(text); m != nil {
+		return m[1], true
+	}
+	return "", false
+}
+
+// messageText returns the text of message content that is either a string or
+// an array of text blocks.
+func messageText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var blocks []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(raw, &blocks); err != nil {
+		return ""
+	}
+	var texts []string
+	for _, b := range blocks {
+		if b.Type == "text" {
+			texts = append(texts, b.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
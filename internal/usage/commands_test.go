@@ -0,0 +1,76 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDecodeCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		src  TranscriptSource
+		line string
+		want string
+	}{
+		{
+			"claude code string content",
+			ClaudeCodeSource{},
+			`{"type":"user","message":{"role":"user","content":"<command-message>commit is running…</command-message>\n<command-name>/commit</command-name>\n<command-args>fix typo</command-args>"}}`,
+			"commit",
+		},
+		{
+			"claude code text blocks",
+			ClaudeCodeSource{},
+			`{"type":"user","message":{"role":"user","content":[{"type":"text","text":"<command-name>/frontend:component</command-name>"}]}}`,
+			"frontend:component",
+		},
+		{
+			"opencode typed command",
+			OpencodeSource{},
+			`{"type":"user","content":"/review-pr 42"}`,
+			"review-pr",
+		},
+		{
+			"opencode slashcommand tool",
+			OpencodeSource{},
+			`{"type":"tool_use","tool_name":"slashcommand","tool_input":{"command":"/deploy staging"}}`,
+			"deploy",
+		},
+		{"path is not a command", OpencodeSource{}, `{"type":"user","content":"/Users/me/file.go is broken"}`, ""},
+		{"assistant echo ignored", ClaudeCodeSource{}, `{"type":"assistant","message":{"content":[{"type":"text","text":"<command-name>/x</command-name>"}]}}`, ""},
+	}
+	for _, tt := range tests {
+		line, ok := tt.src.Decode([]byte(tt.line))
+		if !ok || line.Command != tt.want {
+			t.Errorf("%s: Command = %q, %v; want %q", tt.name, line.Command, ok, tt.want)
+		}
+	}
+}
+
+func TestCollect_CommandsMarkCustom(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, ".claude", "projects", "-project-a")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeJSONL(t, filepath.Join(dir, "s.jsonl"), []string{
+		`{"type":"user","message":{"content":"<command-name>/commit</command-name>"}}`,
+		`{"type":"user","message":{"content":"<command-name>/commit</command-name>"}}`,
+		`{"type":"user","message":{"content":"<command-name>/clear</command-name>"}}`,
+	})
+
+	data, err := (&Collector{HomeDir: home, Commands: []string{"commit"}}).Collect(ScopeAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Commands) != 2 {
+		t.Fatalf("Commands = %+v, want 2 entries", data.Commands)
+	}
+	if c := data.Commands[0]; c.Name != "commit" || c.Count != 2 || !c.Custom {
+		t.Errorf("Commands[0] = %+v, want custom commit x2", c)
+	}
+	if c := data.Commands[1]; c.Name != "clear" || c.Custom {
+		t.Errorf("Commands[1] = %+v, want built-in clear", c)
+	}
+}
@@ -0,0 +1,146 @@
+package usage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DateRange is an explicit time range [Since, Until). A zero bound is open.
+type DateRange struct {
+	Since time.Time
+	Until time.Time
+}
+
+// IsZero reports whether the range is unbounded on both ends.
+func (r DateRange) IsZero() bool {
+	return r.Since.IsZero() && r.Until.IsZero()
+}
+
+// String formats the range with inclusive local days, e.g. "2026-10-01..2026-10-31".
+// Bounds that are not at local midnight are shown with their time.
+func (r DateRange) String() string {
+	var since, until string
+	if !r.Since.IsZero() {
+		since = formatBound(r.Since)
+	}
+	if !r.Until.IsZero() {
+		until = formatBound(r.Until)
+		if local := r.Until.Local(); startOfDay(local).Equal(local) {
+			// Midnight ends the previous day.
+			until = local.AddDate(0, 0, -1).Format(dayLayout)
+		}
+	}
+	return since + ".." + until
+}
+
+func formatBound(t time.Time) string {
+	t = t.Local()
+	if startOfDay(t).Equal(t) {
+		return t.Format(dayLayout)
+	}
+	return t.Format("2006-01-02T15:04")
+}
+
+// Presets lists the named ranges accepted by ParseRange, in display order.
+var Presets = []string{"today", "yesterday", "this week", "last week", "this month", "last month", "this year", "last year"}
+
+// preset returns the named range around now, in now's location. Weeks start on Monday.
+func preset(name string, now time.Time) (DateRange, bool) {
+	loc := now.Location()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	week := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+	year := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, loc)
+	switch name {
+	case "today":
+		return DateRange{day, day.AddDate(0, 0, 1)}, true
+	case "yesterday":
+		return DateRange{day.AddDate(0, 0, -1), day}, true
+	case "this week":
+		return DateRange{week, week.AddDate(0, 0, 7)}, true
+	case "last week":
+		return DateRange{week.AddDate(0, 0, -7), week}, true
+	case "this month":
+		return DateRange{month, month.AddDate(0, 1, 0)}, true
+	case "last month":
+		return DateRange{month.AddDate(0, -1, 0), month}, true
+	case "this year":
+		return DateRange{year, year.AddDate(1, 0, 0)}, true
+	case "last year":
+		return DateRange{year.AddDate(-1, 0, 0), year}, true
+	}
+	return DateRange{}, false
+}
+
+// ParseRange parses a preset name (see Presets), a single date, or "since..until"
+// where either side may be empty. Dates are "2006", "2006-01", "2006-01-02",
+// "2006-01-02T15:04" or RFC 3339, read in now's location unless they carry an
+// offset. Both ends are inclusive: "2026-10-01..2026-10-31" and "2026-10" cover all
+// of October. Calendar arithmetic keeps local midnights across DST changes.
+func ParseRange(expr string, now time.Time) (DateRange, error) {
+	expr = strings.TrimSpace(expr)
+	if r, ok := preset(strings.ToLower(strings.Join(strings.Fields(expr), " ")), now); ok {
+		return r, nil
+	}
+
+	sinceExpr, untilExpr, isRange := strings.Cut(expr, "..")
+	if !isRange {
+		start, end, err := parseBound(expr, now.Location())
+		if err != nil {
+			return DateRange{}, err
+		}
+		if !start.Before(end) {
+			return DateRange{}, fmt.Errorf("date range %q is empty; use since..until for an instant", expr)
+		}
+		return DateRange{start, end}, nil
+	}
+
+	var r DateRange
+	if s := strings.TrimSpace(sinceExpr); s != "" {
+		start, _, err := parseBound(s, now.Location())
+		if err != nil {
+			return DateRange{}, err
+		}
+		r.Since = start
+	}
+	if s := strings.TrimSpace(untilExpr); s != "" {
+		_, end, err := parseBound(s, now.Location())
+		if err != nil {
+			return DateRange{}, err
+		}
+		r.Until = end
+	}
+	if r.IsZero() {
+		return DateRange{}, fmt.Errorf("empty date range %q", expr)
+	}
+	if !r.Since.IsZero() && !r.Until.IsZero() && !r.Since.Before(r.Until) {
+		return DateRange{}, fmt.Errorf("date range %q ends before it starts", expr)
+	}
+	return r, nil
+}
+
+// boundLayouts are the accepted date layouts with the unit each one denotes.
+var boundLayouts = []struct {
+	layout string
+	next   func(time.Time) time.Time
+}{
+	{"2006", func(t time.Time) time.Time { return t.AddDate(1, 0, 0) }},
+	{"2006-01", func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }},
+	{dayLayout, func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }},
+	{"2006-01-02T15:04", func(t time.Time) time.Time { return t.Add(time.Minute) }},
+}
+
+// parseBound parses a date and returns the start of the year, month, day or
+// minute it denotes and the start of the next one. RFC 3339 times denote an instant.
+func parseBound(s string, loc *time.Location) (start, end time.Time, err error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, t, nil
+	}
+	for _, l := range boundLayouts {
+		if t, err := time.ParseInLocation(l.layout, s, loc); err == nil {
+			return t, l.next(t), nil
+		}
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("invalid date %q (want YYYY-MM-DD, YYYY-MM, YYYY, a preset like %q, or since..until)", s, Presets[4])
+}
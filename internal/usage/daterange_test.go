@@ -0,0 +1,127 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseRange(t *testing.T) {
+	loc := time.FixedZone("KST", 9*60*60)
+	// Wednesday.
+	now := time.Date(2026, 10, 14, 15, 30, 0, 0, loc)
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, loc) }
+
+	tests := []struct {
+		expr string
+		want DateRange
+	}{
+		{"today", DateRange{day(2026, 10, 14), day(2026, 10, 15)}},
+		{"yesterday", DateRange{day(2026, 10, 13), day(2026, 10, 14)}},
+		{"This  Week", DateRange{day(2026, 10, 12), day(2026, 10, 19)}},
+		{"last week", DateRange{day(2026, 10, 5), day(2026, 10, 12)}},
+		{"last month", DateRange{day(2026, 9, 1), day(2026, 10, 1)}},
+		{"last year", DateRange{day(2025, 1, 1), day(2026, 1, 1)}},
+		{"2026-10-01..2026-10-07", DateRange{day(2026, 10, 1), day(2026, 10, 8)}},
+		{"2026-10", DateRange{day(2026, 10, 1), day(2026, 11, 1)}},
+		{"2026-09..", DateRange{Since: day(2026, 9, 1)}},
+		{"..2025", DateRange{Until: day(2026, 1, 1)}},
+		{"2026-10-01T09:00..2026-10-01T17:30", DateRange{day(2026, 10, 1).Add(9 * time.Hour), day(2026, 10, 1).Add(17*time.Hour + 31*time.Minute)}},
+		{"2026-10-01T00:00:00Z..", DateRange{Since: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)}},
+	}
+	for _, tt := range tests {
+		got, err := ParseRange(tt.expr, now)
+		if err != nil {
+			t.Errorf("ParseRange(%q) error: %v", tt.expr, err)
+			continue
+		}
+		if !got.Since.Equal(tt.want.Since) || !got.Until.Equal(tt.want.Until) {
+			t.Errorf("ParseRange(%q) = %v – %v, want %v – %v", tt.expr, got.Since, got.Until, tt.want.Since, tt.want.Until)
+		}
+	}
+
+	for _, expr := range []string{"", "..", "soon", "2026-13-01", "2026-10-07..2026-10-01", "2026-10-01T00:00:00Z"} {
+		if _, err := ParseRange(expr, now); err == nil {
+			t.Errorf("ParseRange(%q) should fail", expr)
+		}
+	}
+}
+
+func TestParseRange_DST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database unavailable:", err)
+	}
+	// DST ended on 2026-11-01, so that day has 25 hours.
+	now := time.Date(2026, 11, 3, 12, 0, 0, 0, loc)
+	r, err := ParseRange("2026-11-01", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Until.Sub(r.Since); got != 25*time.Hour {
+		t.Errorf("2026-11-01 spans %v, want 25h", got)
+	}
+	week, _ := ParseRange("this week", now)
+	if want := time.Date(2026, 11, 2, 0, 0, 0, 0, loc); !week.Since.Equal(want) {
+		t.Errorf("this week starts %v, want local midnight %v", week.Since, want)
+	}
+	if want := time.Date(2026, 11, 9, 0, 0, 0, 0, loc); !week.Until.Equal(want) {
+		t.Errorf("this week ends %v, want local midnight %v", week.Until, want)
+	}
+}
+
+func TestDateRange_String(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.Local) }
+	tests := []struct {
+		r    DateRange
+		want string
+	}{
+		{DateRange{day(1), day(8)}, "2026-10-01..2026-10-07"},
+		{DateRange{Since: day(1)}, "2026-10-01.."},
+		{DateRange{day(1).Add(9 * time.Hour), day(1).Add(17 * time.Hour)}, "2026-10-01T09:00..2026-10-01T17:00"},
+	}
+	for _, tt := range tests {
+		if got := tt.r.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestCollect_Range(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, ".claude", "projects", "-project-a")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	today := startOfDay(time.Now())
+	tool := func(name string, at time.Time) string {
+		ts := at.UTC().Format(time.RFC3339)
+		return `{"type":"assistant","timestamp":"` + ts + `","message":{"content":[{"type":"tool_use","name":"` + name + `","input":{}}]}}`
+	}
+	writeJSONL(t, filepath.Join(dir, "s.jsonl"), []string{
+		tool("Bash", today.AddDate(0, 0, -12).Add(time.Hour)), // Prior period.
+		tool("Read", today.AddDate(0, 0, -8).Add(time.Hour)),
+		tool("Read", today.AddDate(0, 0, -6).Add(time.Hour)),
+		tool("Grep", today.Add(time.Hour)), // After the range.
+	})
+
+	c := &Collector{HomeDir: home, Range: DateRange{today.AddDate(0, 0, -10), today.AddDate(0, 0, -5)}}
+	data, err := c.Collect(ScopeAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Tools) != 1 || data.Tools[0].Name != "Read" || data.Tools[0].Count != 2 {
+		t.Fatalf("Tools = %+v, want Read ×2 only", data.Tools)
+	}
+	if !data.HasPrior || data.Tools[0].Prior != 0 {
+		t.Errorf("HasPrior/Prior = %v/%d, want true/0", data.HasPrior, data.Tools[0].Prior)
+	}
+	// The series covers the five days of the range.
+	if got := data.Tools[0].Daily; len(got) != 5 || got[2] != 1 || got[4] != 1 {
+		t.Errorf("Daily = %v, want 5 days with calls on the 3rd and 5th", got)
+	}
+	if c.Tail(ScopeAll) != nil {
+		t.Error("a range that has ended should not be tailed")
+	}
+}
@@ -0,0 +1,115 @@
+package usage
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// maxPendingCalls bounds the tool calls tracked per transcript while awaiting their results.
+const maxPendingCalls = 256
+
+// maxSnippetLen truncates error snippets so that similar errors group together.
+const maxSnippetLen = 80
+
+// extractToolResults pairs tool calls with their results through state and
+// counts the failed calls and their error snippets per tool.
+func extractToolResults(line Line, state *lineState, counts Counts) {
+	for _, call := range line.Calls {
+		if call.ID == "" {
+			continue
+		}
+		if state.Calls == nil || len(state.Calls) >= maxPendingCalls {
+			state.Calls = make(map[string]string)
+		}
+		state.Calls[call.ID] = call.Name
+	}
+	for _, r := range line.Results {
+		name, ok := state.Calls[r.CallID]
+		if !ok {
+			continue
+		}
+		delete(state.Calls, r.CallID)
+		if !r.IsError {
+			continue
+		}
+		counts.Errors[name]++
+		if counts.ErrorSnippets[name] == nil {
+			counts.ErrorSnippets[name] = make(map[string]int)
+		}
+		counts.ErrorSnippets[name][errorSnippet(r.Text)]++
+	}
+	if len(state.Calls) == 0 {
+		state.Calls = nil
+	}
+}
+
+// errorSnippet reduces an error message to its first non-empty line without
+// markup, with whitespace collapsed and truncated to maxSnippetLen runes.
+func errorSnippet(text string) string {
+	text = strings.NewReplacer("<tool_use_error>", "", "</tool_use_error>", "").Replace(text)
+	for line := range strings.Lines(text) {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			continue
+		}
+		if utf8.RuneCountInString(line) > maxSnippetLen {
+			line = string([]rune(line)[:maxSnippetLen-1]) + "…"
+		}
+		return line
+	}
+	return "(no message)"
+}
+
+// displayToolName shows MCP tools as "server/tool" and other tools unchanged.
+func displayToolName(name string) string {
+	if server, tool, ok := splitMCPTool(name); ok {
+		return server + "/" + tool
+	}
+	return name
+}
+
+// errorCounts selects failed calls per tool.
+func errorCounts(c Counts) map[string]int {
+	out := make(map[string]int, len(c.Errors))
+	for name, n := range normalizeCounts(c.Errors) {
+		out[displayToolName(name)] += n
+	}
+	return out
+}
+
+// rankErrors ranks tools by failed calls, attaching their call counts, and ranks
+// the error snippets of each tool for drill-down.
+func (d *UsageData) rankErrors() {
+	calls := make(map[string]int, len(d.counts.Tools))
+	for name, n := range d.counts.Tools {
+		calls[displayToolName(name)] += n
+	}
+	d.ToolErrors = d.rankTrend(errorCounts)
+	for i := range d.ToolErrors {
+		// A result can arrive for a call made before the period; never report more errors than calls.
+		d.ToolErrors[i].Calls = max(calls[d.ToolErrors[i].Name], d.ToolErrors[i].Count)
+	}
+
+	snippets := make(map[string]map[string]int)
+	for name, bySnippet := range d.counts.ErrorSnippets {
+		tool := displayToolName(canonicalName(name))
+		if snippets[tool] == nil {
+			snippets[tool] = make(map[string]int)
+		}
+		for snippet, n := range bySnippet {
+			snippets[tool][snippet] += n
+		}
+	}
+	d.ErrorSnippets = make(map[string][]RankEntry, len(snippets))
+	for tool, counts := range snippets {
+		d.ErrorSnippets[tool] = d.grading.Rank(counts)
+	}
+}
+
+// ErrorRate returns the share of calls that failed, or 0 without calls.
+func (e RankEntry) ErrorRate() float64 {
+	if e.Calls == 0 {
+		return 0
+	}
+	return float64(e.Count) / float64(e.Calls)
+}
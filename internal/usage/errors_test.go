@@ -0,0 +1,76 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// toolUse and toolResult build a Claude Code tool call and its result.
+func toolUse(id, name string) string {
+	return `{"type":"assistant","message":{"content":[{"type":"tool_use","id":"` + id + `","name":"` + name + `","input":{}}]}}`
+}
+
+func toolResult(id string, isError bool, text string) string {
+	e := "false"
+	if isError {
+		e = "true"
+	}
+	return `{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"` + id + `","is_error":` + e + `,"content":"` + text + `"}]}}`
+}
+
+func TestCollect_ToolErrors(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, ".claude", "projects", "-p")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeJSONL(t, filepath.Join(dir, "s.jsonl"), []string{
+		toolUse("t1", "Bash"), toolResult("t1", true, "Exit code 1\\nfoo failed"),
+		toolUse("t2", "Bash"), toolResult("t2", false, "ok"),
+		toolUse("t3", "Bash"), toolResult("t3", true, "Exit code 1\\nbar failed"),
+		toolUse("t4", "Bash"), toolResult("t4", true, "<tool_use_error>Blocked by hook</tool_use_error>"),
+		toolUse("t5", "mcp__github__list_prs"), toolResult("t5", true, "401 Unauthorized"),
+		toolUse("t6", "Read"), toolResult("t6", false, "contents"),
+		// A result without a known call is ignored.
+		toolResult("t0", true, "orphan"),
+	})
+
+	data, err := (&Collector{HomeDir: home}).Collect(ScopeAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(data.ToolErrors) != 2 {
+		t.Fatalf("ToolErrors = %+v, want Bash and github/list_prs", data.ToolErrors)
+	}
+	bash := data.ToolErrors[0]
+	if bash.Name != "Bash" || bash.Count != 3 || bash.Calls != 4 || bash.ErrorRate() != 0.75 {
+		t.Errorf("Bash = %d/%d (%v), want 3/4 (0.75)", bash.Count, bash.Calls, bash.ErrorRate())
+	}
+	if mcp := data.ToolErrors[1]; mcp.Name != "github/list_prs" || mcp.Count != 1 || mcp.Calls != 1 {
+		t.Errorf("MCP tool = %+v, want github/list_prs 1/1", mcp)
+	}
+
+	snippets := data.ErrorSnippets["Bash"]
+	if len(snippets) != 2 || snippets[0].Name != "Exit code 1" || snippets[0].Count != 2 || snippets[1].Name != "Blocked by hook" {
+		t.Errorf("Bash snippets = %+v, want Exit code 1 ×2 then Blocked by hook", snippets)
+	}
+}
+
+func TestErrorSnippet(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"\n\n  Error:   file   not found \nmore", "Error: file not found"},
+		{"<tool_use_error>String not found</tool_use_error>", "String not found"},
+		{"", "(no message)"},
+		{strings.Repeat("x", 100), strings.Repeat("x", maxSnippetLen-1) + "…"},
+	}
+	for _, tt := range tests {
+		if got := errorSnippet(tt.text); got != tt.want {
+			t.Errorf("errorSnippet(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strings"
+)
+
+// GradingModel selects how counts are turned into grades.
+type GradingModel int
+
+const (
+	GradingLog        GradingModel = iota // log(count) relative to the top count
+	GradingPercentile                     // Share of entries used as often or less
+	GradingAbsolute                       // Fixed count thresholds
+	GradingZScore                         // Standard deviations from the mean log count
+
+	gradingModelCount = iota // number of GradingModel values (must remain last)
+)
+
+func (m GradingModel) String() string {
+	switch m {
+	case GradingPercentile:
+		return "percentile"
+	case GradingAbsolute:
+		return "absolute"
+	case GradingZScore:
+		return "zscore"
+	default:
+		return "log"
+	}
+}
+
+// Next returns the next model in the cycle: log → percentile → absolute → zscore → log.
+func (m GradingModel) Next() GradingModel {
+	return (m + 1) % gradingModelCount
+}
+
+// MarshalText encodes the model by name.
+func (m GradingModel) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText decodes a model name such as "percentile", ignoring case.
+func (m *GradingModel) UnmarshalText(text []byte) error {
+	name := strings.ToLower(strings.TrimSpace(string(text)))
+	for g := range GradingModel(gradingModelCount) {
+		if g.String() == name {
+			*m = g
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown grading model %q (want log, percentile, absolute or zscore)", text)
+}
+
+// DefaultThresholds are the minimum counts for grades SSS through D under GradingAbsolute.
+var DefaultThresholds = []int{1000, 300, 100, 30, 10, 3, 1}
+
+// Grading configures how rankings are graded. The zero value grades on the log scale.
+type Grading struct {
+	Model      GradingModel `json:"model"`
+	Thresholds []int        `json:"thresholds,omitempty"` // Minimum counts for SSS through D (GradingAbsolute); nil uses DefaultThresholds
+}
+
+// LoadGrading reads the grading settings from the JSON file at path, for example
+// {"model": "absolute", "thresholds": [500, 200, 100, 50, 20, 5, 1]}.
+// A missing file is not an error and yields the zero Grading.
+func LoadGrading(path string) (Grading, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Grading{}, nil
+		}
+		return Grading{}, fmt.Errorf("failed to read grading settings: %w", err)
+	}
+	var g Grading
+	if err := json.Unmarshal(data, &g); err != nil {
+		return Grading{}, fmt.Errorf("failed to parse grading settings %s: %w", path, err)
+	}
+	if g.Thresholds != nil {
+		if len(g.Thresholds) != len(DefaultThresholds) {
+			return Grading{}, fmt.Errorf("grading settings %s: want %d thresholds (SSS through D), got %d", path, len(DefaultThresholds), len(g.Thresholds))
+		}
+		if !slices.IsSortedFunc(g.Thresholds, func(a, b int) int { return b - a }) {
+			return Grading{}, fmt.Errorf("grading settings %s: thresholds must not increase", path)
+		}
+	}
+	return g, nil
+}
+
+// Describe explains the active model in one line for the ranking header.
+func (g Grading) Describe() string {
+	switch g.Model {
+	case GradingPercentile:
+		return "grade by share of entries used as often or less (top 5% SSS)"
+	case GradingAbsolute:
+		t := g.thresholds()
+		return fmt.Sprintf("grade by fixed counts: SSS ≥%d  SS ≥%d  S ≥%d  A ≥%d  B ≥%d  C ≥%d  D ≥%d", t[0], t[1], t[2], t[3], t[4], t[5], t[6])
+	case GradingZScore:
+		return "grade by standard deviations from the mean log count (SSS ≥ +2σ, B ≥ mean)"
+	default:
+		return "grade by log(count) relative to the top entry"
+	}
+}
+
+func (g Grading) thresholds() []int {
+	if g.Thresholds == nil {
+		return DefaultThresholds
+	}
+	return g.Thresholds
+}
+
+// scorer returns the grade and a bar score in [0, 1] of each count among counts.
+// Unused entries (a count of 0, such as configured MCP servers never called) are
+// graded F and left out of the statistics the relative models compare against.
+func (g Grading) scorer(counts map[string]int) func(count int) (Grade, float64) {
+	used := make([]int, 0, len(counts))
+	for _, c := range counts {
+		if c > 0 {
+			used = append(used, c)
+		}
+	}
+	score := g.modelScorer(used)
+	return func(count int) (Grade, float64) {
+		if count <= 0 {
+			return GradeF, 0
+		}
+		return score(count)
+	}
+}
+
+// modelScorer grades a positive count against the positive counts of the ranking.
+func (g Grading) modelScorer(used []int) func(count int) (Grade, float64) {
+	switch g.Model {
+	case GradingPercentile:
+		slices.Sort(used)
+		return func(count int) (Grade, float64) {
+			atOrBelow, _ := slices.BinarySearch(used, count+1)
+			score := float64(atOrBelow) / float64(len(used))
+			return gradeFromScore(score), score
+		}
+
+	case GradingAbsolute:
+		t := g.thresholds()
+		return func(count int) (Grade, float64) {
+			grade := GradeF
+			for i, at := range t {
+				if count >= at {
+					grade = Grade(i)
+					break
+				}
+			}
+			return grade, min(logScore(count, t[0]), 1)
+		}
+
+	case GradingZScore:
+		var sum, sumSq float64
+		for _, c := range used {
+			l := math.Log(float64(c) + 1)
+			sum += l
+			sumSq += l * l
+		}
+		n := float64(len(used))
+		mean := sum / n
+		sd := math.Sqrt(max(sumSq/n-mean*mean, 0))
+		return func(count int) (Grade, float64) {
+			if sd < 1e-9 {
+				// Every used entry has the same count.
+				return GradeSSS, 1
+			}
+			z := (math.Log(float64(count)+1) - mean) / sd
+			return gradeFromZ(z), 0.5 * (1 + math.Erf(z/math.Sqrt2))
+		}
+
+	default:
+		maxCount := 0
+		for _, c := range used {
+			maxCount = max(maxCount, c)
+		}
+		return func(count int) (Grade, float64) {
+			score := logScore(count, maxCount)
+			return gradeFromScore(score), score
+		}
+	}
+}
+
+func gradeFromZ(z float64) Grade {
+	switch {
+	case z >= 2:
+		return GradeSSS
+	case z >= 1.5:
+		return GradeSS
+	case z >= 1:
+		return GradeS
+	case z >= 0.5:
+		return GradeA
+	case z >= 0:
+		return GradeB
+	case z >= -0.5:
+		return GradeC
+	case z >= -1:
+		return GradeD
+	default:
+		return GradeF
+	}
+}
@@ -0,0 +1,130 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// dominated has one tool used far more than the rest.
+var dominated = map[string]int{"Bash": 5000, "Read": 400, "Edit": 120, "Grep": 40, "Glob": 12, "Write": 3, "Task": 1, "Skill": 0}
+
+func gradesOf(t *testing.T, entries []RankEntry) map[string]Grade {
+	t.Helper()
+	grades := make(map[string]Grade, len(entries))
+	for _, e := range entries {
+		grades[e.Name] = e.Grade
+		if e.Score < 0 || e.Score > 1 {
+			t.Errorf("%s: score %v outside [0, 1]", e.Name, e.Score)
+		}
+	}
+	return grades
+}
+
+func TestGrading_Rank(t *testing.T) {
+	tests := []struct {
+		grading Grading
+		want    map[string]Grade
+	}{
+		{Grading{}, map[string]Grade{"Bash": GradeSSS, "Read": GradeS, "Edit": GradeA, "Grep": GradeB, "Glob": GradeC, "Write": GradeD, "Task": GradeF}},
+		{Grading{Model: GradingPercentile}, map[string]Grade{"Bash": GradeSSS, "Read": GradeSS, "Edit": GradeS, "Grep": GradeA, "Glob": GradeB, "Write": GradeC, "Task": GradeD, "Skill": GradeF}},
+		{Grading{Model: GradingAbsolute}, map[string]Grade{"Bash": GradeSSS, "Read": GradeSS, "Edit": GradeS, "Grep": GradeA, "Glob": GradeB, "Write": GradeC, "Task": GradeD, "Skill": GradeF}},
+		{Grading{Model: GradingAbsolute, Thresholds: []int{5000, 4000, 3000, 2000, 1000, 500, 1}}, map[string]Grade{"Bash": GradeSSS, "Read": GradeD, "Skill": GradeF}},
+		{Grading{Model: GradingZScore}, map[string]Grade{"Bash": GradeSS, "Read": GradeA, "Edit": GradeB, "Glob": GradeD, "Task": GradeF, "Skill": GradeF}},
+	}
+	for _, tt := range tests {
+		got := gradesOf(t, tt.grading.Rank(dominated))
+		for name, want := range tt.want {
+			if got[name] != want {
+				t.Errorf("%s: %s = %s, want %s", tt.grading.Model, name, got[name], want)
+			}
+		}
+	}
+}
+
+func TestGrading_RankTies(t *testing.T) {
+	for m := range GradingModel(gradingModelCount) {
+		entries := Grading{Model: m}.Rank(map[string]int{"a": 7, "b": 7})
+		if m != GradingAbsolute && (entries[0].Grade != GradeSSS || entries[1].Grade != GradeSSS) {
+			t.Errorf("%s: equal counts graded %s/%s, want SSS", m, entries[0].Grade, entries[1].Grade)
+		}
+	}
+}
+
+func TestGrading_RankUnused(t *testing.T) {
+	for m := range GradingModel(gradingModelCount) {
+		g := Grading{Model: m}
+		for _, e := range g.Rank(map[string]int{"a": 0, "b": 0, "c": 0}) {
+			if e.Grade != GradeF || e.Score != 0 {
+				t.Errorf("%s: unused %s graded %s %.2f, want F 0", m, e.Name, e.Grade, e.Score)
+			}
+		}
+
+		grades := gradesOf(t, g.Rank(map[string]int{"idle": 0, "never": 0, "low": 5, "high": 50}))
+		if grades["idle"] != GradeF || grades["never"] != GradeF {
+			t.Errorf("%s: unused entries graded %s/%s among used ones, want F", m, grades["idle"], grades["never"])
+		}
+		if grades["high"] > grades["low"] {
+			t.Errorf("%s: low/high graded %s/%s", m, grades["low"], grades["high"])
+		}
+	}
+
+	// Zeros do not drag down the statistics of the used entries.
+	percentile := gradesOf(t, Grading{Model: GradingPercentile}.Rank(map[string]int{"idle": 0, "never": 0, "low": 5, "high": 50}))
+	if percentile["high"] != GradeSSS || percentile["low"] != GradeA {
+		t.Errorf("percentile low/high = %s/%s, want A/SSS", percentile["low"], percentile["high"])
+	}
+}
+
+func TestGradingModel_Next(t *testing.T) {
+	m := GradingLog
+	for _, want := range []GradingModel{GradingPercentile, GradingAbsolute, GradingZScore, GradingLog} {
+		if m = m.Next(); m != want {
+			t.Errorf("expected %s, got %s", want, m)
+		}
+	}
+}
+
+func TestLoadGrading(t *testing.T) {
+	dir := t.TempDir()
+	if g, err := LoadGrading(filepath.Join(dir, "missing.json")); err != nil || g.Model != GradingLog {
+		t.Errorf("missing file = %+v, %v; want log grading", g, err)
+	}
+
+	tests := []struct {
+		json    string
+		want    GradingModel
+		wantErr bool
+	}{
+		{`{"model":"Percentile"}`, GradingPercentile, false},
+		{`{"model":"zscore"}`, GradingZScore, false},
+		{`{"model":"absolute","thresholds":[500,200,100,50,20,5,1]}`, GradingAbsolute, false},
+		{`{"model":"median"}`, 0, true},
+		{`{"model":"absolute","thresholds":[1,2,3,4,5,6,7]}`, 0, true},
+		{`{"model":"absolute","thresholds":[10,1]}`, 0, true},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, "grading.json")
+		if err := os.WriteFile(path, []byte(tt.json), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		g, err := LoadGrading(path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("LoadGrading(%s) error = %v, wantErr %v", tt.json, err, tt.wantErr)
+			continue
+		}
+		if err == nil && g.Model != tt.want {
+			t.Errorf("LoadGrading(%s) model = %s, want %s", tt.json, g.Model, tt.want)
+		}
+	}
+}
+
+func TestUsageData_SetGrading(t *testing.T) {
+	d := &UsageData{counts: Counts{Tools: dominated}}
+	d.rank()
+	before := gradesOf(t, d.Tools)["Read"]
+	d.SetGrading(Grading{Model: GradingPercentile})
+	if after := gradesOf(t, d.Tools)["Read"]; after == before || after != GradeSS {
+		t.Errorf("Read regraded %s → %s, want SS under percentile", before, after)
+	}
+}
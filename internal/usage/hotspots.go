@@ -0,0 +1,240 @@
+package usage
+
+import (
+	"encoding/json"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// HotspotGroup selects what the Hotspots ranking lists.
+type HotspotGroup int
+
+const (
+	HotspotsBash    HotspotGroup = iota // Normalized Bash command prefixes
+	HotspotsReads                       // Files read in the current project
+	HotspotsEdits                       // Files edited or written in the current project
+	HotspotsDomains                     // Domains fetched with WebFetch
+
+	hotspotGroupCount = iota // number of HotspotGroup values (must remain last)
+)
+
+func (g HotspotGroup) String() string {
+	switch g {
+	case HotspotsReads:
+		return "Reads"
+	case HotspotsEdits:
+		return "Edits"
+	case HotspotsDomains:
+		return "Domains"
+	default:
+		return "Bash"
+	}
+}
+
+// Next returns the next grouping in the cycle: Bash → Reads → Edits → Domains → Bash.
+func (g HotspotGroup) Next() HotspotGroup {
+	return (g + 1) % hotspotGroupCount
+}
+
+// subcommandPrograms are programs whose first argument names what they do, so
+// "go build" and "go test" are counted apart.
+var subcommandPrograms = map[string]bool{
+	"apt": true, "aws": true, "brew": true, "bun": true, "bundle": true, "cargo": true,
+	"deno": true, "docker": true, "dotnet": true, "gcloud": true, "gh": true, "git": true,
+	"go": true, "gradle": true, "helm": true, "kubectl": true, "make": true, "mvn": true,
+	"nix": true, "npm": true, "npx": true, "pip": true, "pnpm": true, "poetry": true,
+	"rails": true, "rake": true, "swift": true, "systemctl": true, "terraform": true,
+	"uv": true, "yarn": true,
+}
+
+// scriptRunners are the subcommands that take a script name, as in "npm run build".
+var scriptRunners = map[string]bool{"run": true, "exec": true, "x": true}
+
+// argWordPattern matches a plain subcommand word rather than a flag, path or value.
+var argWordPattern = regexp.MustCompile(`^[a-z][a-z0-9:_-]*$`)
+
+// envAssignPattern matches a leading environment assignment such as CGO_ENABLED=0.
+var envAssignPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*=`)
+
+// hotspotInput holds the tool_use arguments hotspots are read from. opencode uses
+// filePath where Claude Code uses file_path.
+type hotspotInput struct {
+	Command      string `json:"command"`
+	FilePath     string `json:"file_path"`
+	FilePathAlt  string `json:"filePath"`
+	NotebookPath string `json:"notebook_path"`
+	URL          string `json:"url"`
+}
+
+// extractHotspots counts the commands, files and domains named in the tool calls of a line.
+func extractHotspots(line Line, counts Counts) {
+	for _, call := range line.Calls {
+		var in hotspotInput
+		if len(call.Input) == 0 || json.Unmarshal(call.Input, &in) != nil {
+			continue
+		}
+		path := in.FilePath
+		if path == "" {
+			path = in.FilePathAlt
+		}
+		switch strings.ToLower(call.Name) {
+		case "bash":
+			for _, prefix := range bashPrefixes(in.Command) {
+				counts.BashCommands[prefix]++
+			}
+		case "read":
+			if path != "" {
+				counts.FileReads[filepath.Clean(path)]++
+			}
+		case "edit", "multiedit", "write":
+			if path != "" {
+				counts.FileEdits[filepath.Clean(path)]++
+			}
+		case "notebookedit":
+			if in.NotebookPath != "" {
+				counts.FileEdits[filepath.Clean(in.NotebookPath)]++
+			}
+		case "webfetch":
+			if domain := urlDomain(in.URL); domain != "" {
+				counts.WebDomains[domain]++
+			}
+		}
+	}
+}
+
+// bashPrefixes returns the normalized prefix of each command in a shell command
+// line, split at &&, ||, ;, | and newlines: the program name, plus its subcommand
+// for programs like git and go, and the script for "npm run".
+// Example: "cd web && CI=1 npm run test -- --watch=false" → ["cd", "npm run test"]
+func bashPrefixes(command string) []string {
+	var prefixes []string
+	for _, segment := range splitShell(command) {
+		fields := strings.Fields(segment)
+		for len(fields) > 0 && envAssignPattern.MatchString(fields[0]) {
+			fields = fields[1:]
+		}
+		if len(fields) > 0 && (fields[0] == "sudo" || fields[0] == "time" || fields[0] == "exec") {
+			fields = fields[1:]
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		prefix := []string{filepath.Base(fields[0])}
+		if subcommandPrograms[prefix[0]] && len(fields) > 1 && argWordPattern.MatchString(fields[1]) {
+			prefix = append(prefix, fields[1])
+			if scriptRunners[fields[1]] && len(fields) > 2 && argWordPattern.MatchString(fields[2]) {
+				prefix = append(prefix, fields[2])
+			}
+		}
+		prefixes = append(prefixes, strings.Join(prefix, " "))
+	}
+	return prefixes
+}
+
+// splitShell splits a command line into commands at unquoted &&, ||, ;, | and
+// newlines, and drops subshell parentheses.
+func splitShell(command string) []string {
+	var (
+		segments []string
+		cur      strings.Builder
+		quote    rune
+	)
+	flush := func() {
+		if s := strings.Trim(strings.TrimSpace(cur.String()), "()"); s != "" {
+			segments = append(segments, s)
+		}
+		cur.Reset()
+	}
+	runes := []rune(command)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+			cur.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote = r
+			cur.WriteRune(r)
+		case r == ';' || r == '\n' || r == '|':
+			flush()
+			if r == '|' && i+1 < len(runes) && runes[i+1] == '|' {
+				i++
+			}
+		case r == '&' && i+1 < len(runes) && runes[i+1] == '&':
+			flush()
+			i++
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	flush()
+	return segments
+}
+
+// urlDomain returns the lowercase host of a URL without a leading "www.", or "".
+func urlDomain(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// hotspotCounts selects the counts of g. Files are limited to those within the
+// current project and named relative to it, when a project is known.
+func (d *UsageData) hotspotCounts(g HotspotGroup) countsOf {
+	return func(c Counts) map[string]int {
+		switch g {
+		case HotspotsReads:
+			return projectFiles(c.FileReads, d.projectPath)
+		case HotspotsEdits:
+			return projectFiles(c.FileEdits, d.projectPath)
+		case HotspotsDomains:
+			return c.WebDomains
+		default:
+			return c.BashCommands
+		}
+	}
+}
+
+// projectFiles keeps the files within project, relative to it. Without a project
+// every file is kept as recorded.
+func projectFiles(files map[string]int, project string) map[string]int {
+	if project == "" {
+		return files
+	}
+	out := make(map[string]int)
+	for path, n := range files {
+		if !filepath.IsAbs(path) || !isWithin(path, project) {
+			continue
+		}
+		if rel, err := filepath.Rel(project, path); err == nil {
+			out[rel] += n
+		}
+	}
+	return out
+}
+
+// AllowRule returns the permission rule that would allow the hotspot name of g,
+// in the syntax of the permissions.allow setting.
+func AllowRule(g HotspotGroup, name string) string {
+	switch g {
+	case HotspotsReads, HotspotsEdits:
+		tool := "Read"
+		if g == HotspotsEdits {
+			tool = "Edit"
+		}
+		if filepath.IsAbs(name) {
+			return tool + "(/" + name + ")" // "//" anchors at the filesystem root.
+		}
+		return tool + "(./" + name + ")"
+	case HotspotsDomains:
+		return "WebFetch(domain:" + name + ")"
+	default:
+		return "Bash(" + name + ":*)"
+	}
+}
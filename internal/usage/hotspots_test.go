@@ -0,0 +1,118 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestBashPrefixes(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+	}{
+		{"go build ./...", []string{"go build"}},
+		{"npm test", []string{"npm test"}},
+		{"cd web && CI=1 npm run test -- --watch=false", []string{"cd", "npm run test"}},
+		{"git status; git diff --stat | head -20", []string{"git status", "git diff", "head"}},
+		{"/usr/local/bin/go test -run TestX ./internal/...", []string{"go test"}},
+		{"ls -la || true", []string{"ls", "true"}},
+		{`echo "a && b; c" > out.txt`, []string{"echo"}},
+		{"git -C repo log", []string{"git"}},
+		{"sudo apt install jq", []string{"apt install"}},
+		{"(cd sub && make)\npython3 script.py", []string{"cd", "make", "python3"}},
+		{"  ", nil},
+	}
+	for _, tt := range tests {
+		if got := bashPrefixes(tt.command); !slices.Equal(got, tt.want) {
+			t.Errorf("bashPrefixes(%q) = %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestURLDomain(t *testing.T) {
+	tests := map[string]string{
+		"https://www.Example.com/docs?q=1": "example.com",
+		"https://pkg.go.dev/net/url":       "pkg.go.dev",
+		"http://localhost:8080/":           "localhost",
+		"not a url":                        "",
+	}
+	for raw, want := range tests {
+		if got := urlDomain(raw); got != want {
+			t.Errorf("urlDomain(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestAllowRule(t *testing.T) {
+	tests := []struct {
+		g    HotspotGroup
+		name string
+		want string
+	}{
+		{HotspotsBash, "go test", "Bash(go test:*)"},
+		{HotspotsReads, "internal/usage/collect.go", "Read(./internal/usage/collect.go)"},
+		{HotspotsEdits, "/etc/hosts", "Edit(//etc/hosts)"},
+		{HotspotsDomains, "pkg.go.dev", "WebFetch(domain:pkg.go.dev)"},
+	}
+	for _, tt := range tests {
+		if got := AllowRule(tt.g, tt.name); got != tt.want {
+			t.Errorf("AllowRule(%s, %q) = %q, want %q", tt.g, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCollect_Hotspots(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, ".claude", "projects", "-work-app")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	call := func(name, input string) string {
+		return `{"type":"assistant","message":{"content":[{"type":"tool_use","name":"` + name + `","input":` + input + `}]}}`
+	}
+	writeJSONL(t, filepath.Join(dir, "s.jsonl"), []string{
+		call("Bash", `{"command":"go test ./... && go vet ./..."}`),
+		call("Bash", `{"command":"go test -run TestX ./internal/usage"}`),
+		call("Read", `{"file_path":"/work/app/main.go"}`),
+		call("Read", `{"file_path":"/work/app/main.go"}`),
+		call("Read", `{"file_path":"/etc/hosts"}`),
+		call("Edit", `{"file_path":"/work/app/main.go","old_string":"a","new_string":"b"}`),
+		call("Write", `{"file_path":"/work/app/docs/README.md","content":""}`),
+		call("WebFetch", `{"url":"https://pkg.go.dev/slices","prompt":"summarize"}`),
+	})
+
+	data, err := (&Collector{HomeDir: home, ProjectPath: "/work/app"}).Collect(ScopeAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := func(g HotspotGroup) map[string]int {
+		m := make(map[string]int)
+		for _, e := range data.Hotspots[g] {
+			m[e.Name] = e.Count
+		}
+		return m
+	}
+	tests := []struct {
+		g    HotspotGroup
+		want map[string]int
+	}{
+		{HotspotsBash, map[string]int{"go test": 2, "go vet": 1}},
+		{HotspotsReads, map[string]int{"main.go": 2}},
+		{HotspotsEdits, map[string]int{"main.go": 1, filepath.Join("docs", "README.md"): 1}},
+		{HotspotsDomains, map[string]int{"pkg.go.dev": 1}},
+	}
+	for _, tt := range tests {
+		got := counts(tt.g)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s = %v, want %v", tt.g, got, tt.want)
+			continue
+		}
+		for name, n := range tt.want {
+			if got[name] != n {
+				t.Errorf("%s[%s] = %d, want %d", tt.g, name, got[name], n)
+			}
+		}
+	}
+}
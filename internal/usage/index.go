@@ -0,0 +1,288 @@
+package usage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// indexVersion identifies the on-disk index format and the extraction rules that
+// produced its counts. Bump it whenever either changes so stale indexes are rebuilt.
+const indexVersion = 8
+
+// indexFileName is the index file inside the cache directory.
+const indexFileName = "usage-index.json"
+
+// maxLineSize skips transcript lines longer than this instead of parsing them.
+const maxLineSize = 1 << 20
+
+// bucketSize is the span of an index bucket. Every time zone offset is a multiple
+// of 15 minutes, so local midnight always falls on a bucket boundary and per-day
+// totals can be computed in any time zone without reparsing.
+const bucketSize = 15 * time.Minute
+
+// bucketLayout keys index buckets by the UTC time they start at.
+const bucketLayout = "2006-01-02T15:04"
+
+// bucketKey returns the key of the bucket t falls in.
+func bucketKey(t time.Time) string {
+	return t.UTC().Truncate(bucketSize).Format(bucketLayout)
+}
+
+// dayLayout formats local calendar days.
+const dayLayout = "2006-01-02"
+
+// usageIndex caches per-file parse progress and the counts found so far.
+type usageIndex struct {
+	Version int                   `json:"version"`
+	Files   map[string]*fileIndex `json:"files"`
+}
+
+// fileIndex records how far a transcript has been parsed and what it contained.
+type fileIndex struct {
+	Offset  int64             `json:"offset"`         // Byte offset just past the last parsed line
+	ModTime time.Time         `json:"mtime"`          // Modification time when Offset was recorded
+	Buckets map[string]Counts `json:"buckets"`        // Counts per 15-minute UTC bucket; "" holds lines without a timestamp
+	State   lineState         `json:"state"`          // Extraction context at Offset
+	Start   time.Time         `json:"start,omitzero"` // First timestamp seen
+	End     time.Time         `json:"end,omitzero"`   // Last timestamp seen
+}
+
+// newIndex returns an empty index.
+func newIndex() *usageIndex {
+	return &usageIndex{Version: indexVersion, Files: make(map[string]*fileIndex)}
+}
+
+// loadIndex reads the index at path. A missing, unreadable or outdated index
+// yields an empty one, so every transcript is parsed from the start.
+func loadIndex(path string) *usageIndex {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return newIndex()
+	}
+	var idx usageIndex
+	if err := json.Unmarshal(data, &idx); err != nil || idx.Version != indexVersion || idx.Files == nil {
+		return newIndex()
+	}
+	for _, f := range idx.Files {
+		if f.Buckets == nil {
+			f.Buckets = make(map[string]Counts)
+		}
+		// Empty maps are omitted on disk; reallocate them so buckets can grow.
+		for key, bucket := range f.Buckets {
+			f.Buckets[key] = newCounts().merged(bucket)
+		}
+	}
+	return &idx
+}
+
+// save atomically writes the index to path, dropping entries for deleted files.
+func (idx *usageIndex) save(path string) error {
+	for p := range idx.Files {
+		if _, err := os.Stat(p); errors.Is(err, os.ErrNotExist) {
+			delete(idx.Files, p)
+		}
+	}
+
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), indexFileName+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// updateFileIndex brings entry up to date with file, parsing only bytes
+// appended since entry.Offset. The entry is rebuilt from scratch when the file
+// shrank, was rewritten in place, or no longer ends a line at the recorded offset.
+// A nil entry starts a new one. A trailing unterminated line is returned as a
+// separate, unrecorded entry, since the line may still be growing.
+func updateFileIndex(ctx context.Context, file transcriptFile, entry *fileIndex) (*fileIndex, *fileIndex, error) {
+	partial := &fileIndex{Buckets: make(map[string]Counts)}
+	info, err := os.Stat(file.path)
+	if err != nil {
+		return entry, partial, err
+	}
+	f, err := os.Open(file.path)
+	if err != nil {
+		return entry, partial, err
+	}
+	defer f.Close()
+
+	if entry == nil || !entry.appendable(f, info) {
+		entry = &fileIndex{Buckets: make(map[string]Counts)}
+	}
+	if entry.Offset == info.Size() && entry.ModTime.Equal(info.ModTime()) {
+		return entry, partial, nil
+	}
+	if _, err := f.Seek(entry.Offset, io.SeekStart); err != nil {
+		return entry, partial, err
+	}
+
+	scratch := newCounts()
+	consumed, tail, err := readLines(ctx, f, func(line []byte) {
+		entry.add(file.source, line, scratch)
+	})
+	entry.Offset += consumed
+	entry.ModTime = info.ModTime()
+	if len(tail) > 0 {
+		// The partial line must not advance the recorded state.
+		partial.State = *entry.State.clone()
+		partial.add(file.source, tail, scratch)
+	}
+	return entry, partial, err
+}
+
+// appendable reports whether the file can be parsed incrementally from e.Offset.
+func (e *fileIndex) appendable(f *os.File, info os.FileInfo) bool {
+	switch {
+	case info.Size() < e.Offset:
+		return false // Truncated or replaced by a shorter file.
+	case info.Size() == e.Offset:
+		return e.ModTime.Equal(info.ModTime()) // Same size but rewritten in place.
+	case e.Offset == 0:
+		return true
+	}
+	// The byte before the offset must still end a line, or the file was replaced.
+	var b [1]byte
+	if _, err := f.ReadAt(b[:], e.Offset-1); err != nil || b[0] != '\n' {
+		return false
+	}
+	return true
+}
+
+// add decodes a line with src and extracts its usage into its bucketSize bucket,
+// extending the session's time span. scratch is reused across lines to avoid
+// allocating buckets for lines without usage.
+func (e *fileIndex) add(src TranscriptSource, raw []byte, scratch Counts) {
+	line, ok := src.Decode(raw)
+	if !ok {
+		return
+	}
+	scratch.reset()
+	extractLine(line, &e.State, scratch)
+	ts, dated := line.Timestamp, !line.Timestamp.IsZero()
+	if dated {
+		if e.Start.IsZero() || ts.Before(e.Start) {
+			e.Start = ts
+		}
+		if ts.After(e.End) {
+			e.End = ts
+		}
+	}
+	if scratch.Empty() {
+		return
+	}
+
+	key := ""
+	if dated {
+		key = bucketKey(ts)
+	}
+	bucket, ok := e.Buckets[key]
+	if !ok {
+		bucket = newCounts()
+		e.Buckets[key] = bucket
+	}
+	bucket.merge(scratch)
+}
+
+// sumRange adds the counts of the buckets in w into counts, attributing them to
+// the transcript's project, its session and the local day. A bucket belongs to
+// the window its start falls in, so cutoffs inside a bucket are precise to
+// bucketSize. Lines without a timestamp count only toward windows open at the
+// end. The session keeps its full time span even if part of it is outside w.
+func (e *fileIndex) sumRange(w window, path string, counts Counts) {
+	project := e.State.project(path)
+	session := newSession(path, project)
+	session.Start, session.End = e.Start, e.End
+	for key, bucket := range e.Buckets {
+		if key == "" {
+			if w.until.IsZero() {
+				counts.addLine(bucket, project, "")
+				session.Messages += bucket.messageCount()
+			}
+			continue
+		}
+		start, err := time.Parse(bucketLayout, key)
+		if err != nil || !w.contains(start) {
+			continue
+		}
+		local := start.Local()
+		counts.addLine(bucket, project, local.Format(dayLayout))
+		n := bucket.messageCount()
+		session.Messages += n
+		session.Hours[local.Hour()] += n
+	}
+	if session.Messages > 0 {
+		counts.addSession(session)
+	}
+}
+
+// readLines calls fn for each complete line in r, skipping lines longer than
+// maxLineSize. It returns the number of bytes consumed through the last newline
+// and the trailing unterminated line, if any.
+func readLines(ctx context.Context, r io.Reader, fn func(line []byte)) (int64, []byte, error) {
+	br := bufio.NewReaderSize(r, 64*1024)
+	var (
+		consumed  int64
+		pending   int64  // Bytes of the current line read so far
+		long      []byte // Current line when it spans several buffer fills
+		oversized bool
+	)
+	for n := 0; ; n++ {
+		// Check for cancellation periodically; large transcripts have many lines.
+		if n%1024 == 0 && ctx.Err() != nil {
+			return consumed, nil, ctx.Err()
+		}
+
+		chunk, err := br.ReadSlice('\n')
+		pending += int64(len(chunk))
+		switch {
+		case errors.Is(err, bufio.ErrBufferFull):
+			if !oversized {
+				long = append(long, chunk...)
+				if len(long) > maxLineSize {
+					oversized, long = true, nil
+				}
+			}
+			continue
+		case errors.Is(err, io.EOF):
+			if oversized {
+				return consumed, nil, nil
+			}
+			return consumed, append(long, chunk...), nil
+		case err != nil:
+			return consumed, nil, err
+		}
+
+		consumed += pending
+		line := bytes.TrimSuffix(chunk, []byte("\n"))
+		if long != nil {
+			line = append(long, line...)
+		}
+		if !oversized && len(line) <= maxLineSize {
+			fn(line)
+		}
+		pending, long, oversized = 0, nil, false
+	}
+}
@@ -0,0 +1,235 @@
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const readLine = `{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Read","input":{}}]}}`
+
+func setupIndexed(t *testing.T) (c *Collector, path string) {
+	t.Helper()
+	home := t.TempDir()
+	dir := filepath.Join(home, ".claude", "projects", "-project-a")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path = filepath.Join(dir, "session.jsonl")
+	writeJSONL(t, path, []string{readLine, readLine})
+	return &Collector{HomeDir: home, CacheDir: t.TempDir()}, path
+}
+
+func toolCount(t *testing.T, c *Collector, name string) int {
+	t.Helper()
+	data, err := c.Collect(ScopeAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data.counts.Tools[name]
+}
+
+// tamperIndex rewrites the saved index so that the Read count of path is n.
+// A later Collect that reports n proves the file was not reparsed.
+func tamperIndex(t *testing.T, c *Collector, path string, n int) {
+	t.Helper()
+	idx := loadIndex(filepath.Join(c.CacheDir, indexFileName))
+	entry := idx.Files[path]
+	if entry == nil {
+		t.Fatalf("index has no entry for %s", path)
+	}
+	entry.Buckets = map[string]Counts{"": {Tools: map[string]int{"Read": n}}}
+	if err := idx.save(filepath.Join(c.CacheDir, indexFileName)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func appendLines(t *testing.T, path string, lines ...string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for _, line := range lines {
+		f.WriteString(line + "\n")
+	}
+}
+
+func TestIndex_ParsesOnlyAppendedBytes(t *testing.T) {
+	c, path := setupIndexed(t)
+	if got := toolCount(t, c, "Read"); got != 2 {
+		t.Fatalf("Read = %d, want 2", got)
+	}
+
+	tamperIndex(t, c, path, 100)
+	appendLines(t, path, readLine)
+
+	if got := toolCount(t, c, "Read"); got != 101 {
+		t.Errorf("Read = %d, want 101 (indexed 100 + 1 appended)", got)
+	}
+}
+
+func TestIndex_AppendsToReloadedBucket(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, ".claude", "projects", "-project-a")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "s.jsonl")
+	ts := time.Now().UTC().Format(time.RFC3339)
+	read := `{"type":"assistant","timestamp":"` + ts + `","message":{"content":[{"type":"tool_use","name":"Read","input":{}}]}}`
+	task := `{"type":"assistant","timestamp":"` + ts + `","message":{"content":[{"type":"tool_use","name":"Task","input":{"subagent_type":"reviewer"}}]}}`
+	writeJSONL(t, path, []string{read})
+
+	// The first load stores a bucket without agents; appending an agent to the
+	// same hour must grow the reloaded bucket.
+	c := &Collector{HomeDir: home, CacheDir: t.TempDir()}
+	toolCount(t, c, "Read")
+	appendLines(t, path, task)
+	data, err := c.Collect(ScopeAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.counts.Agents["reviewer"] != 1 {
+		t.Errorf("reviewer = %d, want 1", data.counts.Agents["reviewer"])
+	}
+}
+
+func TestIndex_RebuildsWhenFileShrinks(t *testing.T) {
+	c, path := setupIndexed(t)
+	toolCount(t, c, "Read")
+	tamperIndex(t, c, path, 100)
+
+	writeJSONL(t, path, []string{readLine})
+
+	if got := toolCount(t, c, "Read"); got != 1 {
+		t.Errorf("Read = %d, want 1 after the file was truncated", got)
+	}
+}
+
+func TestIndex_RebuildsOnVersionChange(t *testing.T) {
+	c, path := setupIndexed(t)
+	toolCount(t, c, "Read")
+	tamperIndex(t, c, path, 100)
+
+	indexPath := filepath.Join(c.CacheDir, indexFileName)
+	data, err := os.ReadFile(indexPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	raw["version"] = indexVersion - 1
+	data, _ = json.Marshal(raw)
+	if err := os.WriteFile(indexPath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := toolCount(t, c, "Read"); got != 2 {
+		t.Errorf("Read = %d, want 2 from a rebuilt index", got)
+	}
+}
+
+func TestIndex_PartialLineCountedButNotIndexed(t *testing.T) {
+	c, path := setupIndexed(t)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(readLine) // No trailing newline yet.
+	f.Close()
+
+	if got := toolCount(t, c, "Read"); got != 3 {
+		t.Fatalf("Read = %d, want 3", got)
+	}
+	info, _ := os.Stat(path)
+	entry := loadIndex(filepath.Join(c.CacheDir, indexFileName)).Files[path]
+	if want := info.Size() - int64(len(readLine)); entry.Offset != want {
+		t.Errorf("Offset = %d, want %d (before the partial line)", entry.Offset, want)
+	}
+
+	// Completing the line must not count it twice.
+	appendLines(t, path, "")
+	if got := toolCount(t, c, "Read"); got != 3 {
+		t.Errorf("Read = %d, want 3 after the line was completed", got)
+	}
+}
+
+func TestIndex_CutoffUsesBuckets(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, ".claude", "projects", "-project-a")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	stamp := func(ts time.Time) string {
+		return `{"type":"assistant","timestamp":"` + ts.UTC().Format(time.RFC3339) + `","message":{"content":[{"type":"tool_use","name":"Read","input":{}}]}}`
+	}
+	now := time.Now()
+	writeJSONL(t, filepath.Join(dir, "s.jsonl"), []string{stamp(now.Add(-48 * time.Hour)), stamp(now.Add(-time.Hour)), stamp(now)})
+
+	c := &Collector{HomeDir: home, CacheDir: t.TempDir()}
+	if got := toolCount(t, c, "Read"); got != 3 {
+		t.Fatalf("All: Read = %d, want 3", got)
+	}
+	c.Period = PeriodDay
+	if got := toolCount(t, c, "Read"); got != 2 {
+		t.Errorf("24h from the index: Read = %d, want 2", got)
+	}
+}
+
+func TestFileIndex_SumRangeHalfHourZone(t *testing.T) {
+	local := time.Local
+	time.Local = time.FixedZone("IST", 5*60*60+30*60)
+	t.Cleanup(func() { time.Local = local })
+
+	// Local midnight of March 10 is 18:30 UTC on March 9, inside an hour.
+	midnight := time.Date(2026, 3, 10, 0, 0, 0, 0, time.Local)
+	entry := &fileIndex{Buckets: map[string]Counts{
+		bucketKey(midnight.Add(-time.Minute)):     {Tools: map[string]int{"Read": 1}},
+		bucketKey(midnight):                       {Tools: map[string]int{"Read": 10}},
+		bucketKey(midnight.Add(20 * time.Minute)): {Tools: map[string]int{"Read": 100}},
+	}}
+
+	counts := newCounts()
+	entry.sumRange(window{since: midnight, until: midnight.AddDate(0, 0, 1)}, "p", counts)
+	if counts.Tools["Read"] != 110 {
+		t.Errorf("Read = %d, want 110 (buckets from local midnight on)", counts.Tools["Read"])
+	}
+	if got := counts.Daily["2026-03-10"].Tools["Read"]; got != 110 || len(counts.Daily) != 1 {
+		t.Errorf("Daily = %v, want 110 on 2026-03-10 only", counts.Daily)
+	}
+
+	// A range ending now keeps the bucket now falls in.
+	counts = newCounts()
+	entry.sumRange(window{since: midnight, until: midnight.Add(25 * time.Minute)}, "p", counts)
+	if counts.Tools["Read"] != 110 {
+		t.Errorf("Read until now = %d, want 110 including the current bucket", counts.Tools["Read"])
+	}
+}
+
+func TestReadLines_SkipsOversizedLines(t *testing.T) {
+	input := "a\n" + strings.Repeat("x", maxLineSize+10) + "\nb\nc"
+	var lines []string
+	consumed, tail, err := readLines(context.Background(), strings.NewReader(input), func(line []byte) {
+		lines = append(lines, string(line))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(lines, ",") != "a,b" {
+		t.Errorf("lines = %v, want [a b]", lines)
+	}
+	if string(tail) != "c" {
+		t.Errorf("tail = %q, want %q", tail, "c")
+	}
+	if want := int64(len(input) - 1); consumed != want {
+		t.Errorf("consumed = %d, want %d", consumed, want)
+	}
+}
@@ -0,0 +1,56 @@
+package usage
+
+import "strings"
+
+// mcpPrefix marks MCP tool names, which take the form mcp__<server>__<tool>.
+const mcpPrefix = "mcp__"
+
+// splitMCPTool splits an MCP tool name into its server and tool parts.
+func splitMCPTool(name string) (server, tool string, ok bool) {
+	rest, found := strings.CutPrefix(name, mcpPrefix)
+	if !found {
+		return "", "", false
+	}
+	server, tool, found = strings.Cut(rest, "__")
+	if !found || server == "" || tool == "" {
+		return "", "", false
+	}
+	return server, tool, true
+}
+
+// MCPToolServerName returns the server name as it appears in MCP tool names.
+// Claude Code replaces characters other than letters, digits, '_' and '-' with '_'.
+func MCPToolServerName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// splitMCP separates MCP calls from built-in tool counts. It returns the remaining
+// tool counts, call totals per server and per-server tool counts. Every configured
+// server is present in servers, with zero calls if it was never used.
+func splitMCP(tools map[string]int, configured []string) (builtin, servers map[string]int, perServer map[string]map[string]int) {
+	builtin = make(map[string]int, len(tools))
+	servers = make(map[string]int)
+	perServer = make(map[string]map[string]int)
+	for _, name := range configured {
+		servers[MCPToolServerName(name)] += 0
+	}
+	for name, n := range tools {
+		server, tool, ok := splitMCPTool(name)
+		if !ok {
+			builtin[name] += n
+			continue
+		}
+		servers[server] += n
+		if perServer[server] == nil {
+			perServer[server] = make(map[string]int)
+		}
+		perServer[server][tool] += n
+	}
+	return builtin, servers, perServer
+}
@@ -0,0 +1,61 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSplitMCPTool(t *testing.T) {
+	tests := []struct {
+		name, server, tool string
+		ok                 bool
+	}{
+		{"mcp__github__create_issue", "github", "create_issue", true},
+		{"mcp__claude_ai_Notion__search", "claude_ai_Notion", "search", true},
+		{"mcp__broken", "", "", false},
+		{"Read", "", "", false},
+	}
+	for _, tt := range tests {
+		server, tool, ok := splitMCPTool(tt.name)
+		if server != tt.server || tool != tt.tool || ok != tt.ok {
+			t.Errorf("splitMCPTool(%q) = %q, %q, %v; want %q, %q, %v", tt.name, server, tool, ok, tt.server, tt.tool, tt.ok)
+		}
+	}
+}
+
+func TestCollect_MCPGroupedByServer(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, ".claude", "projects", "-project-a")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeJSONL(t, filepath.Join(dir, "s.jsonl"), []string{
+		`{"type":"assistant","message":{"content":[{"type":"tool_use","name":"mcp__github__create_issue","input":{}},{"type":"tool_use","name":"mcp__github__list_prs","input":{}}]}}`,
+		`{"type":"assistant","message":{"content":[{"type":"tool_use","name":"mcp__github__list_prs","input":{}},{"type":"tool_use","name":"Read","input":{}}]}}`,
+	})
+
+	c := &Collector{HomeDir: home, MCPServers: []string{"github", "my.docs"}}
+	data, err := c.Collect(ScopeAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(data.Tools) != 1 || data.Tools[0].Name != "Read" {
+		t.Errorf("Tools = %+v, want only Read", data.Tools)
+	}
+	if len(data.MCP) != 2 {
+		t.Fatalf("MCP = %+v, want 2 servers", data.MCP)
+	}
+	if data.MCP[0].Name != "github" || data.MCP[0].Count != 3 {
+		t.Errorf("MCP[0] = %+v, want github with 3 calls", data.MCP[0])
+	}
+	if data.MCP[1].Name != "my_docs" || data.MCP[1].Count != 0 || data.MCP[1].Grade != GradeF {
+		t.Errorf("MCP[1] = %+v, want unused my_docs graded F", data.MCP[1])
+	}
+
+	tools := data.MCPTools["github"]
+	if len(tools) != 2 || tools[0].Name != "list_prs" || tools[0].Count != 2 {
+		t.Errorf("github tools = %+v, want list_prs=2 first", tools)
+	}
+}
@@ -0,0 +1,84 @@
+package usage
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+)
+
+// OpencodeSource reads the Claude Code-compatible transcripts opencode writes to
+// ~/.claude/transcripts/, one JSONL file per session. They carry no project, so
+// they are only read for all projects.
+//
+// Format: {"type":"tool_use","tool_name":"task","tool_input":{...}} for tool calls and
+// {"type":"user","content":"..."} for messages. Lines in Claude Code's own format
+// are decoded as such.
+type OpencodeSource struct{}
+
+// opencodeLine parses an opencode-format transcript JSONL line.
+type opencodeLine struct {
+	Type      string          `json:"type"`
+	Cwd       string          `json:"cwd"`
+	ToolName  string          `json:"tool_name"`
+	ToolInput json.RawMessage `json:"tool_input"`
+	Content   json.RawMessage `json:"content"`
+	Message   json.RawMessage `json:"message"`
+}
+
+// commandInput extracts the command from an opencode slashcommand tool_input.
+type commandInput struct {
+	Command string `json:"command"`
+	Name    string `json:"name"`
+}
+
+// Files lists the transcripts in ~/.claude/transcripts, or none for a single project.
+func (OpencodeSource) Files(homeDir string, project ProjectFilter) []string {
+	if project.Path != "" {
+		return nil
+	}
+	return jsonlFiles(filepath.Join(homeDir, ".claude", "transcripts"))
+}
+
+// Decode parses an opencode line. Slash commands are either typed at the start of
+// a user message or invoked through the slashcommand tool.
+func (OpencodeSource) Decode(line []byte) (Line, bool) {
+	var ol opencodeLine
+	if err := json.Unmarshal(line, &ol); err != nil {
+		return Line{}, false
+	}
+	if len(ol.Message) > 0 {
+		return ClaudeCodeSource{}.Decode(line)
+	}
+	l := Line{Cwd: ol.Cwd}
+	l.Timestamp, _ = extractTimestamp(line)
+	switch ol.Type {
+	case "tool_use":
+		if ol.ToolName == "" {
+			break
+		}
+		l.Calls = []ToolCall{{Name: ol.ToolName, Input: ol.ToolInput}}
+		if ol.ToolName == "slashcommand" {
+			var input commandInput
+			if err := json.Unmarshal(ol.ToolInput, &input); err == nil {
+				cmd := input.Command
+				if cmd == "" {
+					cmd = input.Name
+				}
+				if fields := strings.Fields(strings.TrimPrefix(cmd, "/")); len(fields) > 0 {
+					l.Command = fields[0]
+				}
+			}
+		}
+	case "user":
+		l.Role = ol.Type
+		text := messageText(ol.Content)
+		if name, ok := commandFromTag(text); ok {
+			l.Command = name
+		} else if m := typedCommandPattern.FindStringSubmatch(text); m != nil {
+			l.Command = m[1]
+		}
+	case "assistant":
+		l.Role = ol.Type
+	}
+	return l, true
+}
@@ -0,0 +1,52 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpencodeSource_Decode(t *testing.T) {
+	lines, counts := extractFixture(t, OpencodeSource{}, "opencode.jsonl")
+	if len(lines) != 9 {
+		t.Fatalf("decoded %d lines, want 9", len(lines))
+	}
+	if l := lines[0]; l.Role != "user" || l.Command != "review-pr" || l.Timestamp.IsZero() {
+		t.Errorf("typed command line = %+v, want a dated user message invoking review-pr", l)
+	}
+	if l := lines[7]; l.Command != "" {
+		t.Errorf("a path is not a command, got %q", l.Command)
+	}
+
+	if counts.Agents["librarian"] != 1 || counts.Skills["git-master"] != 1 {
+		t.Errorf("agents/skills = %v %v, want librarian and git-master once", counts.Agents, counts.Skills)
+	}
+	if counts.Commands["review-pr"] != 1 || counts.Commands["deploy"] != 1 || len(counts.Commands) != 2 {
+		t.Errorf("commands = %v, want review-pr and deploy", counts.Commands)
+	}
+	// Claude Code-format lines in the same file are decoded too.
+	for _, tool := range []string{"task", "skill", "slashcommand", "read", "Grep"} {
+		if counts.Tools[tool] != 1 {
+			t.Errorf("tools = %v, want %s once", counts.Tools, tool)
+		}
+	}
+	if counts.Messages["user"] != 2 || counts.Messages["assistant"] != 2 {
+		t.Errorf("messages = %v, want 2 prompts and 2 replies", counts.Messages)
+	}
+}
+
+func TestOpencodeSource_Files(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, ".claude", "transcripts")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeJSONL(t, filepath.Join(dir, "ses_1.jsonl"), nil)
+
+	if got := (OpencodeSource{}).Files(home, ProjectFilter{}); len(got) != 1 {
+		t.Errorf("Files(all) = %v, want ses_1.jsonl", got)
+	}
+	if got := (OpencodeSource{}).Files(home, ProjectFilter{Path: "/home/me/a"}); got != nil {
+		t.Errorf("Files(project) = %v, want none: opencode transcripts carry no project", got)
+	}
+}
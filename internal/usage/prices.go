@@ -0,0 +1,79 @@
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+)
+
+// Price is the cost in USD per million tokens of each kind.
+type Price struct {
+	Input         float64 `json:"input"`
+	Output        float64 `json:"output"`
+	CacheRead     float64 `json:"cache_read"`
+	CacheCreation float64 `json:"cache_creation"`
+}
+
+// PriceTable maps model name prefixes to prices. The longest matching prefix wins,
+// so "claude-opus-4-5" can override "claude-opus-4".
+type PriceTable map[string]Price
+
+// DefaultPrices holds list prices for Claude models, used when no price file overrides them.
+var DefaultPrices = PriceTable{
+	"claude-opus-4-6":   {Input: 5, Output: 25, CacheRead: 0.5, CacheCreation: 6.25},
+	"claude-opus-4-5":   {Input: 5, Output: 25, CacheRead: 0.5, CacheCreation: 6.25},
+	"claude-opus-4":     {Input: 15, Output: 75, CacheRead: 1.5, CacheCreation: 18.75},
+	"claude-sonnet-4":   {Input: 3, Output: 15, CacheRead: 0.3, CacheCreation: 3.75},
+	"claude-3-7-sonnet": {Input: 3, Output: 15, CacheRead: 0.3, CacheCreation: 3.75},
+	"claude-3-5-sonnet": {Input: 3, Output: 15, CacheRead: 0.3, CacheCreation: 3.75},
+	"claude-haiku-4-5":  {Input: 1, Output: 5, CacheRead: 0.1, CacheCreation: 1.25},
+	"claude-3-5-haiku":  {Input: 0.8, Output: 4, CacheRead: 0.08, CacheCreation: 1},
+}
+
+// LoadPrices returns DefaultPrices overlaid with the entries of the JSON price file
+// at path. A missing file is not an error.
+func LoadPrices(path string) (PriceTable, error) {
+	table := maps.Clone(DefaultPrices)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return table, nil
+		}
+		return table, fmt.Errorf("failed to read price table: %w", err)
+	}
+	var custom PriceTable
+	if err := json.Unmarshal(data, &custom); err != nil {
+		return table, fmt.Errorf("failed to parse price table %s: %w", path, err)
+	}
+	maps.Copy(table, custom)
+	return table, nil
+}
+
+// Lookup returns the price for model by longest prefix match.
+func (t PriceTable) Lookup(model string) (Price, bool) {
+	best, found := "", false
+	for prefix := range t {
+		if strings.HasPrefix(model, prefix) && len(prefix) >= len(best) {
+			best, found = prefix, true
+		}
+	}
+	return t[best], found
+}
+
+// Cost estimates the USD cost of token usage per model. Unknown models cost nothing.
+func (t PriceTable) Cost(tokens ModelTokens) float64 {
+	var total float64
+	for model, u := range tokens {
+		p, ok := t.Lookup(model)
+		if !ok {
+			continue
+		}
+		total += (float64(u.Input)*p.Input +
+			float64(u.Output)*p.Output +
+			float64(u.CacheRead)*p.CacheRead +
+			float64(u.CacheCreation)*p.CacheCreation) / 1e6
+	}
+	return total
+}
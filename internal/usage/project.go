@@ -0,0 +1,158 @@
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCwdProbeLines bounds how many lines of a transcript are read to find its cwd.
+const maxCwdProbeLines = 50
+
+// ProjectFilter limits usage to the sessions of one project.
+type ProjectFilter struct {
+	Path   string // Project directory; empty matches every session
+	Nested bool   // Also match sessions in subdirectories and git worktrees of Path
+}
+
+// Matches reports whether a session whose working directory is cwd belongs to the project.
+func (p ProjectFilter) Matches(cwd string) bool {
+	if p.Path == "" {
+		return true
+	}
+	if cwd == "" {
+		return false
+	}
+	root, cwd := filepath.Clean(p.Path), filepath.Clean(cwd)
+	if cwd == root {
+		return true
+	}
+	if !p.Nested {
+		return false
+	}
+	return isWithin(cwd, root) || isWithin(worktreeMain(cwd), root)
+}
+
+// isWithin reports whether path is dir or below it.
+func isWithin(path, dir string) bool {
+	if path == "" {
+		return false
+	}
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// projectCache remembers what was learned about transcript directories and
+// worktrees, which do not change while ccfg runs.
+var projectCache = struct {
+	sync.Mutex
+	cwds      map[string]dirCwdProbe // Transcript directory -> session working directory
+	worktrees map[string]string      // Working directory -> main worktree ("" if none)
+}{cwds: make(map[string]dirCwdProbe), worktrees: make(map[string]string)}
+
+// dirCwdProbe is the working directory found in a transcript directory. A
+// directory without one is probed again once its modification time changes.
+type dirCwdProbe struct {
+	cwd     string
+	modTime time.Time
+}
+
+// dirCwd returns the working directory recorded in the transcripts of dir, or ""
+// if none records one yet. Claude Code names the directory after the session's
+// working directory, so the first one found applies to the whole directory.
+func dirCwd(dir string) string {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return ""
+	}
+	projectCache.Lock()
+	probe, ok := projectCache.cwds[dir]
+	projectCache.Unlock()
+	if ok && (probe.cwd != "" || probe.modTime.Equal(info.ModTime())) {
+		return probe.cwd
+	}
+
+	probe = dirCwdProbe{modTime: info.ModTime()}
+	for _, path := range jsonlFiles(dir) {
+		if probe.cwd = fileCwd(path); probe.cwd != "" {
+			break
+		}
+	}
+	projectCache.Lock()
+	projectCache.cwds[dir] = probe
+	projectCache.Unlock()
+	return probe.cwd
+}
+
+// fileCwd returns the first cwd recorded in the leading lines of a transcript.
+func fileCwd(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), maxLineSize)
+	for n := 0; n < maxCwdProbeLines && sc.Scan(); n++ {
+		var l struct {
+			Cwd string `json:"cwd"`
+		}
+		if json.Unmarshal(sc.Bytes(), &l) == nil && l.Cwd != "" {
+			return l.Cwd
+		}
+	}
+	return ""
+}
+
+// worktreeMain returns the main working tree of the git worktree containing dir,
+// or "" if dir is not inside a linked worktree. A linked worktree has a .git file
+// pointing to <main>/.git/worktrees/<name>.
+func worktreeMain(dir string) string {
+	projectCache.Lock()
+	main, ok := projectCache.worktrees[dir]
+	projectCache.Unlock()
+	if ok {
+		return main
+	}
+	for d := dir; ; d = filepath.Dir(d) {
+		if info, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			if !info.IsDir() {
+				main = linkedMain(d)
+			}
+			break
+		}
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+	projectCache.Lock()
+	projectCache.worktrees[dir] = main
+	projectCache.Unlock()
+	return main
+}
+
+// linkedMain reads the .git file of the worktree at dir and returns its main working tree.
+func linkedMain(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, ".git"))
+	if err != nil {
+		return ""
+	}
+	gitdir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return ""
+	}
+	gitdir = strings.TrimSpace(gitdir)
+	if !filepath.IsAbs(gitdir) {
+		gitdir = filepath.Join(dir, gitdir)
+	}
+	// <main>/.git/worktrees/<name>
+	common := filepath.Dir(filepath.Dir(gitdir))
+	if filepath.Base(filepath.Dir(gitdir)) != "worktrees" || filepath.Base(common) != ".git" {
+		return ""
+	}
+	return filepath.Dir(common)
+}
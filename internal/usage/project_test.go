@@ -0,0 +1,79 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestProjectFilter_Matches(t *testing.T) {
+	root := t.TempDir()
+	main := filepath.Join(root, "app")
+	wt := filepath.Join(root, "app-feature")
+	for _, dir := range []string{filepath.Join(main, ".git", "worktrees", "feature"), filepath.Join(wt, "src")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gitFile := "gitdir: " + filepath.Join(main, ".git", "worktrees", "feature") + "\n"
+	if err := os.WriteFile(filepath.Join(wt, ".git"), []byte(gitFile), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		filter ProjectFilter
+		cwd    string
+		want   bool
+	}{
+		{ProjectFilter{}, "/anywhere", true},
+		{ProjectFilter{Path: main}, main, true},
+		{ProjectFilter{Path: main}, main + "/", true},
+		{ProjectFilter{Path: main}, filepath.Join(main, "cmd"), false},
+		{ProjectFilter{Path: main}, "", false},
+		{ProjectFilter{Path: main, Nested: true}, filepath.Join(main, "cmd"), true},
+		{ProjectFilter{Path: main, Nested: true}, main + "-other", false},
+		{ProjectFilter{Path: main, Nested: true}, filepath.Join(wt, "src"), true},
+		{ProjectFilter{Path: main}, wt, false},
+		{ProjectFilter{Path: wt, Nested: true}, main, false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.Matches(tt.cwd); got != tt.want {
+			t.Errorf("%+v.Matches(%q) = %v, want %v", tt.filter, tt.cwd, got, tt.want)
+		}
+	}
+}
+
+func TestClaudeCodeSource_FilesByCwd(t *testing.T) {
+	home := t.TempDir()
+	session := func(dir, cwd string) string {
+		path := filepath.Join(home, ".claude", "projects", dir)
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		var lines []string
+		if cwd != "" {
+			lines = []string{`{"type":"summary"}`, `{"type":"user","cwd":"` + cwd + `","message":{"content":"hi"}}`}
+		}
+		writeJSONL(t, filepath.Join(path, "s.jsonl"), lines)
+		return filepath.Join(path, "s.jsonl")
+	}
+	dotted := session("-home-me-my-app-renamed", "/home/me/my.app")
+	session("-home-me-my-app", "/home/me/my-app") // Same encoded name, different project.
+	nested := session("-home-me-my-app-web", "/home/me/my.app/web")
+	fresh := session("-home-me-my-app--", "")
+
+	got := ClaudeCodeSource{}.Files(home, ProjectFilter{Path: "/home/me/my.app"})
+	if !slices.Equal(got, []string{dotted}) {
+		t.Errorf("Files(my.app) = %v, want [%s]", got, dotted)
+	}
+	got = ClaudeCodeSource{}.Files(home, ProjectFilter{Path: "/home/me/my.app", Nested: true})
+	if !slices.Equal(got, []string{dotted, nested}) {
+		t.Errorf("Files(my.app, nested) = %v, want [%s %s]", got, dotted, nested)
+	}
+	// Without a recorded cwd, the directory name decides.
+	got = ClaudeCodeSource{}.Files(home, ProjectFilter{Path: "/home/me/my_app.."})
+	if !slices.Equal(got, []string{fresh}) {
+		t.Errorf("Files(my_app..) = %v, want [%s]", got, fresh)
+	}
+}
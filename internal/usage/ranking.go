@@ -7,25 +7,24 @@ import (
 
 // Rank assigns grades on a log scale to invocation counts and sorts entries in descending order.
 func Rank(counts map[string]int) []RankEntry {
+	return Grading{}.Rank(counts)
+}
+
+// Rank grades invocation counts under g's model and sorts entries in descending order.
+func (g Grading) Rank(counts map[string]int) []RankEntry {
 	if len(counts) == 0 {
 		return nil
 	}
 
-	maxCount := 0
-	for _, c := range counts {
-		if c > maxCount {
-			maxCount = c
-		}
-	}
-
+	score := g.scorer(counts)
 	entries := make([]RankEntry, 0, len(counts))
 	for name, count := range counts {
-		score := logScore(count, maxCount)
+		grade, s := score(count)
 		entries = append(entries, RankEntry{
-			Name:     name,
-			Count:    count,
-			Grade:    gradeFromScore(score),
-			LogScore: score,
+			Name:  name,
+			Count: count,
+			Grade: grade,
+			Score: s,
 		})
 	}
 
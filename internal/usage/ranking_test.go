@@ -1,6 +1,7 @@
 package usage
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -149,6 +150,17 @@ func TestTimePeriod_Next(t *testing.T) {
 	}
 }
 
+func TestDataScope_Next(t *testing.T) {
+	// All → Project → Project+ → All (cycle)
+	want := []DataScope{ScopeProject, ScopeProjectTree, ScopeAll}
+	s := ScopeAll
+	for _, w := range want {
+		if s = s.Next(); s != w {
+			t.Errorf("expected %s, got %s", w, s)
+		}
+	}
+}
+
 func TestTimePeriod_Cutoff(t *testing.T) {
 	// PeriodAll returns zero time.
 	if c := PeriodAll.Cutoff(); !c.IsZero() {
@@ -203,25 +215,25 @@ func TestCollectWithCutoff_FiltersOldLines(t *testing.T) {
 	writeJSONL(t, filepath.Join(dir, "session.jsonl"), lines)
 
 	// No cutoff: both counted.
-	counts, err := collectAgents(home, "", time.Time{})
+	counts, err := collectCounts(context.Background(), newIndex(), home, ProjectFilter{}, time.Time{})
 	if err != nil {
 		t.Fatal(err)
 	}
-	if counts["code-reviewer"] != 1 || counts["librarian"] != 1 {
-		t.Errorf("no cutoff: code-reviewer=%d, librarian=%d", counts["code-reviewer"], counts["librarian"])
+	if counts.Agents["code-reviewer"] != 1 || counts.Agents["librarian"] != 1 {
+		t.Errorf("no cutoff: code-reviewer=%d, librarian=%d", counts.Agents["code-reviewer"], counts.Agents["librarian"])
 	}
 
 	// Cutoff at 24h ago: only recent line counted.
 	cutoff := now.Add(-24 * time.Hour)
-	counts, err = collectAgents(home, "", cutoff)
+	counts, err = collectCounts(context.Background(), newIndex(), home, ProjectFilter{}, cutoff)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if counts["code-reviewer"] != 1 {
-		t.Errorf("with cutoff: code-reviewer=%d, want 1", counts["code-reviewer"])
+	if counts.Agents["code-reviewer"] != 1 {
+		t.Errorf("with cutoff: code-reviewer=%d, want 1", counts.Agents["code-reviewer"])
 	}
-	if counts["librarian"] != 0 {
-		t.Errorf("with cutoff: librarian=%d, want 0 (filtered out)", counts["librarian"])
+	if counts.Agents["librarian"] != 0 {
+		t.Errorf("with cutoff: librarian=%d, want 0 (filtered out)", counts.Agents["librarian"])
 	}
 }
 
@@ -244,14 +256,14 @@ func TestCollectTools_WithCutoff(t *testing.T) {
 
 	// With 24h cutoff: only Read counted.
 	cutoff := now.Add(-24 * time.Hour)
-	counts, err := collectTools(home, "", cutoff)
+	counts, err := collectCounts(context.Background(), newIndex(), home, ProjectFilter{}, cutoff)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if counts["Read"] != 1 {
-		t.Errorf("Read: got %d, want 1", counts["Read"])
+	if counts.Tools["Read"] != 1 {
+		t.Errorf("Read: got %d, want 1", counts.Tools["Read"])
 	}
-	if counts["Bash"] != 0 {
-		t.Errorf("Bash: got %d, want 0 (filtered out)", counts["Bash"])
+	if counts.Tools["Bash"] != 0 {
+		t.Errorf("Bash: got %d, want 0 (filtered out)", counts.Tools["Bash"])
 	}
 }
@@ -0,0 +1,247 @@
+package usage
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Session summarizes one transcript: Claude Code and opencode write a file per session.
+type Session struct {
+	ID       string    // Transcript file name without extension
+	Path     string    // Transcript file
+	Project  string    // Project the session ran in
+	Start    time.Time // First timestamp in the transcript
+	End      time.Time // Last timestamp in the transcript
+	Messages int       // User prompts and assistant replies in the period
+	Hours    [24]int   // Messages per local hour of day
+}
+
+// Duration returns the time from the first to the last timestamp.
+func (s Session) Duration() time.Duration {
+	if s.Start.IsZero() || s.End.IsZero() {
+		return 0
+	}
+	return s.End.Sub(s.Start)
+}
+
+// merge combines two partial views of the same session.
+func (s Session) merge(o Session) Session {
+	if s.Start.IsZero() || (!o.Start.IsZero() && o.Start.Before(s.Start)) {
+		s.Start = o.Start
+	}
+	if o.End.After(s.End) {
+		s.End = o.End
+	}
+	s.Messages += o.Messages
+	for h, n := range o.Hours {
+		s.Hours[h] += n
+	}
+	if s.Project == "" {
+		s.Project = o.Project
+	}
+	return s
+}
+
+// newSession starts the summary of the transcript at path.
+func newSession(path, project string) Session {
+	return Session{
+		ID:      strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		Path:    path,
+		Project: project,
+	}
+}
+
+// addSession merges s into the session with the same transcript.
+func (c Counts) addSession(s Session) {
+	if prev, ok := c.Sessions[s.Path]; ok {
+		s = prev.merge(s)
+	}
+	c.Sessions[s.Path] = s
+}
+
+// SessionStats summarizes the sessions active in the period.
+type SessionStats struct {
+	Count    int           // Sessions with at least one message in the period
+	Messages int           // Messages in the period
+	Duration time.Duration // Summed session durations
+}
+
+// AvgDuration returns the mean session duration.
+func (s SessionStats) AvgDuration() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Duration / time.Duration(s.Count)
+}
+
+// AvgMessages returns the mean number of messages per session.
+func (s SessionStats) AvgMessages() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return float64(s.Messages) / float64(s.Count)
+}
+
+// extractMessage counts a user prompt or assistant reply of the main conversation.
+// Tool results, meta lines and subagent sidechains are not messages, and Claude Code's
+// one-line-per-content-block replies are counted once per message ID.
+func extractMessage(line Line, state *lineState, counts Counts) {
+	if line.Meta || line.Sidechain {
+		return
+	}
+	switch line.Role {
+	case "user":
+		if len(line.Results) > 0 {
+			return
+		}
+	case "assistant":
+		if id := line.MessageID; id != "" {
+			if id == state.LastReply {
+				return
+			}
+			state.LastReply = id
+		}
+	default:
+		return
+	}
+	counts.Messages[line.Role]++
+}
+
+// messageCount returns the messages counted for all roles.
+func (c Counts) messageCount() int {
+	n := 0
+	for _, m := range c.Messages {
+		n += m
+	}
+	return n
+}
+
+// rankSessions builds the session rankings and summary from the counted sessions.
+func (d *UsageData) rankSessions() {
+	d.SessionStats = SessionStats{}
+	for _, s := range d.counts.Sessions {
+		d.SessionStats.Count++
+		d.SessionStats.Messages += s.Messages
+		d.SessionStats.Duration += s.Duration()
+	}
+
+	d.SessionRanks = make(map[SessionGroup][]RankEntry, sessionGroupCount)
+	for g := range SessionGroup(sessionGroupCount) {
+		d.SessionRanks[g] = d.sessionTrend(d.grading.Rank(sessionCounts(d.counts, g)), g)
+	}
+}
+
+// sessionCounts counts sessions per project or start day, or messages per hour of day.
+func sessionCounts(c Counts, g SessionGroup) map[string]int {
+	counts := make(map[string]int)
+	for _, s := range c.Sessions {
+		switch g {
+		case SessionsByHour:
+			for h, n := range s.Hours {
+				if n > 0 {
+					counts[hourName(h)] += n
+				}
+			}
+		default:
+			if key := sessionKey(s, g); key != "" {
+				counts[key]++
+			}
+		}
+	}
+	return counts
+}
+
+// sessionTrend fills the daily series of sessions started and the prior-period counts.
+// Hours of the day have no daily series.
+func (d *UsageData) sessionTrend(entries []RankEntry, g SessionGroup) []RankEntry {
+	if d.HasPrior {
+		prior := sessionCounts(d.prior, g)
+		for i := range entries {
+			entries[i].Prior = prior[entries[i].Name]
+		}
+	}
+	if g == SessionsByHour {
+		return entries
+	}
+
+	days := d.days()
+	pos := make(map[string]int, len(days))
+	for i, day := range days {
+		pos[day] = i
+	}
+	index := make(map[string]int, len(entries))
+	for i := range entries {
+		index[entries[i].Name] = i
+		entries[i].Daily = make([]int, len(days))
+	}
+	for _, s := range d.counts.Sessions {
+		j, ok := index[sessionKey(s, g)]
+		if !ok || s.Start.IsZero() {
+			continue
+		}
+		if i, ok := pos[s.Start.Local().Format(dayLayout)]; ok {
+			entries[j].Daily[i]++
+		}
+	}
+	return entries
+}
+
+// SessionsFor lists the sessions behind a row of the g ranking, most messages first.
+// Each entry's Name is the local start time and Session points to the session.
+func (d *UsageData) SessionsFor(g SessionGroup, name string) []RankEntry {
+	byID := make(map[string]int)
+	sessions := make(map[string]*Session)
+	for _, s := range d.counts.Sessions {
+		if g == SessionsByHour {
+			if h, ok := parseHourName(name); !ok || s.Hours[h] == 0 {
+				continue
+			}
+		} else if sessionKey(s, g) != name {
+			continue
+		}
+		byID[s.Path] = s.Messages
+		sessions[s.Path] = &s
+	}
+	entries := d.grading.Rank(byID)
+	for i := range entries {
+		s := sessions[entries[i].Name]
+		entries[i].Session = s
+		entries[i].Name = "—"
+		if !s.Start.IsZero() {
+			entries[i].Name = s.Start.Local().Format("01-02 15:04")
+		}
+	}
+	// Rank breaks ties by path; list equally long sessions newest first instead.
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		return a.Count > b.Count || (a.Count == b.Count && a.Session.Start.After(b.Session.Start))
+	})
+	return entries
+}
+
+// sessionKey returns the project or local start day of s.
+func sessionKey(s Session, g SessionGroup) string {
+	if g == SessionsByDay {
+		if s.Start.IsZero() {
+			return ""
+		}
+		return s.Start.Local().Format(dayLayout)
+	}
+	return s.Project
+}
+
+// hourName labels an hour of day, e.g. "09:00".
+func hourName(h int) string {
+	return fmt.Sprintf("%02d:00", h)
+}
+
+func parseHourName(name string) (int, bool) {
+	var h int
+	if _, err := fmt.Sscanf(name, "%02d:00", &h); err != nil || h < 0 || h > 23 {
+		return 0, false
+	}
+	return h, true
+}
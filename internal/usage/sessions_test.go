@@ -0,0 +1,116 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExtractMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want int
+	}{
+		{"user prompt", `{"type":"user","message":{"role":"user","content":"fix the bug"}}`, 1},
+		{"tool result", `{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}`, 0},
+		{"meta", `{"type":"user","isMeta":true,"message":{"content":"<local-command-stdout></local-command-stdout>"}}`, 0},
+		{"assistant", `{"type":"assistant","message":{"id":"msg_1","content":[{"type":"text","text":"hi"}]}}`, 1},
+		{"sidechain", `{"type":"assistant","isSidechain":true,"message":{"id":"msg_2","content":[]}}`, 0},
+		{"opencode user", `{"type":"user","content":"/commit"}`, 1},
+		{"summary", `{"type":"summary","summary":"Fixing the bug"}`, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var src TranscriptSource = ClaudeCodeSource{}
+			if strings.HasPrefix(tt.name, "opencode") {
+				src = OpencodeSource{}
+			}
+			line, _ := src.Decode([]byte(tt.line))
+			counts := newCounts()
+			extractMessage(line, &lineState{}, counts)
+			if got := counts.messageCount(); got != tt.want {
+				t.Errorf("messages = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	// Claude Code writes a line per content block of the same reply.
+	state, counts := &lineState{}, newCounts()
+	line, _ := ClaudeCodeSource{}.Decode([]byte(`{"type":"assistant","message":{"id":"msg_1","content":[]}}`))
+	for range 3 {
+		extractMessage(line, state, counts)
+	}
+	if got := counts.Messages["assistant"]; got != 1 {
+		t.Errorf("repeated reply counted %d times, want 1", got)
+	}
+}
+
+func TestCollect_Sessions(t *testing.T) {
+	home := t.TempDir()
+	dirA := filepath.Join(home, ".claude", "projects", "-code-alpha")
+	dirB := filepath.Join(home, ".claude", "projects", "-code-beta")
+	for _, dir := range []string{dirA, dirB} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	start := time.Now().Add(-3 * time.Hour).Truncate(time.Minute)
+	at := func(d time.Duration) string { return start.Add(d).UTC().Format(time.RFC3339) }
+	msg := func(typ, ts, id string) string {
+		return `{"type":"` + typ + `","timestamp":"` + ts + `","message":{"id":"` + id + `","content":"x"}}`
+	}
+	writeJSONL(t, filepath.Join(dirA, "s1.jsonl"), []string{
+		msg("user", at(0), ""),
+		msg("assistant", at(time.Minute), "m1"),
+		msg("assistant", at(time.Minute), "m1"),
+		msg("user", at(40*time.Minute), ""),
+		msg("assistant", at(45*time.Minute), "m2"),
+	})
+	writeJSONL(t, filepath.Join(dirA, "s2.jsonl"), []string{
+		msg("user", at(2*time.Hour), ""),
+		msg("assistant", at(2*time.Hour+10*time.Minute), "m3"),
+	})
+	writeJSONL(t, filepath.Join(dirB, "s3.jsonl"), []string{
+		msg("user", at(time.Hour), ""),
+	})
+
+	data, err := (&Collector{HomeDir: home, CacheDir: t.TempDir()}).Collect(ScopeAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats := data.SessionStats
+	if stats.Count != 3 || stats.Messages != 7 {
+		t.Fatalf("stats = %+v, want 3 sessions with 7 messages", stats)
+	}
+	if got := stats.AvgDuration(); got != (45*time.Minute+10*time.Minute)/3 {
+		t.Errorf("AvgDuration = %v, want %v", got, (55*time.Minute)/3)
+	}
+
+	projects := data.SessionRanks[SessionsByProject]
+	if len(projects) != 2 || projects[0].Name != "-code-alpha" || projects[0].Count != 2 {
+		t.Fatalf("projects = %+v, want -code-alpha with 2 sessions first", projects)
+	}
+	sessions := data.SessionsFor(SessionsByProject, "-code-alpha")
+	if len(sessions) != 2 || sessions[0].Session.ID != "s1" || sessions[0].Count != 4 {
+		t.Fatalf("-code-alpha sessions = %+v, want s1 with 4 messages first", sessions)
+	}
+	if got := sessions[0].Session.Duration(); got != 45*time.Minute {
+		t.Errorf("s1 duration = %v, want 45m", got)
+	}
+	if got, want := sessions[0].Name, start.Format("01-02 15:04"); got != want {
+		t.Errorf("s1 name = %q, want its start %q", got, want)
+	}
+
+	hour := hourName(start.Add(2 * time.Hour).Hour())
+	var found bool
+	for _, s := range data.SessionsFor(SessionsByHour, hour) {
+		found = found || s.Session.ID == "s2"
+	}
+	if !found {
+		t.Errorf("SessionsFor(%s) should include s2", hour)
+	}
+}
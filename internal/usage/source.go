@@ -0,0 +1,124 @@
+package usage
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TranscriptSource reads the transcripts of one agent CLI. A source finds its
+// transcript files and decodes their lines into Lines; usage is then extracted
+// from Lines the same way for every source.
+type TranscriptSource interface {
+	// Files lists the transcript files under homeDir of the sessions that match project.
+	Files(homeDir string, project ProjectFilter) []string
+	// Decode parses one transcript line. ok is false for lines the source cannot
+	// read, which are skipped.
+	Decode(line []byte) (l Line, ok bool)
+}
+
+// DefaultSources are the transcript sources read when Collector.Sources is nil.
+var DefaultSources = []TranscriptSource{ClaudeCodeSource{}, OpencodeSource{}}
+
+// Line is a transcript line decoded into the events usage is counted from.
+type Line struct {
+	Role      string     // "user" or "assistant" for conversation messages, empty otherwise
+	Timestamp time.Time  // Zero if the line has none
+	Cwd       string     // Working directory of the session, if recorded on the line
+	Meta      bool       // Injected by the CLI rather than typed by the user
+	Sidechain bool       // Part of a subagent conversation
+	MessageID string     // Assistant message the line belongs to; lines of one message share it
+	Model     string     // Model that generated an assistant message
+	Usage     TokenUsage // Tokens reported for the message
+	Command   string     // Slash command invoked, without the leading slash
+	Calls     []ToolCall
+	Results   []ToolResult
+}
+
+// ToolCall is a tool invocation.
+type ToolCall struct {
+	ID    string          // Pairs the call with its result; empty if the source does not record it
+	Name  string          // Tool name as recorded, e.g. "Read" or "mcp__github__list_prs"
+	Input json.RawMessage // Tool arguments
+}
+
+// ToolResult is the outcome of a tool call.
+type ToolResult struct {
+	CallID  string // ID of the ToolCall
+	IsError bool
+	Text    string // Output text; only set for failed calls
+}
+
+// transcriptFile is a transcript and the source that reads it.
+type transcriptFile struct {
+	path   string
+	source TranscriptSource
+}
+
+// transcripts selects the transcript files to read.
+type transcripts struct {
+	sources []TranscriptSource
+	homeDir string
+	project ProjectFilter
+}
+
+// files lists the transcripts of every source. With a cutoff, files last
+// modified before it are skipped since none of their lines can be in range.
+func (t transcripts) files(cutoff time.Time) []transcriptFile {
+	var files []transcriptFile
+	for _, src := range t.sources {
+		for _, path := range src.Files(t.homeDir, t.project) {
+			// ModTime optimization: skip files entirely if older than cutoff.
+			if !cutoff.IsZero() {
+				if info, err := os.Stat(path); err == nil && info.ModTime().Before(cutoff) {
+					continue
+				}
+			}
+			files = append(files, transcriptFile{path, src})
+		}
+	}
+	return files
+}
+
+// jsonlFiles lists the .jsonl files directly inside dirs.
+func jsonlFiles(dirs ...string) []string {
+	var files []string
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && filepath.Ext(entry.Name()) == ".jsonl" {
+				files = append(files, filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+	return files
+}
+
+// timestampKey is the byte pattern used to locate timestamps in JSONL lines.
+var timestampKey = []byte(`"timestamp":"`)
+
+// extractTimestamp extracts the timestamp from a JSONL line without full JSON parsing.
+// It looks for "timestamp":"..." and parses the value as RFC3339.
+func extractTimestamp(line []byte) (time.Time, bool) {
+	idx := bytes.Index(line, timestampKey)
+	if idx < 0 {
+		return time.Time{}, false
+	}
+
+	start := idx + len(timestampKey)
+	end := bytes.IndexByte(line[start:], '"')
+	if end < 0 {
+		return time.Time{}, false
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, string(line[start:start+end]))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -0,0 +1,74 @@
+package usage
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// extractFixture decodes every line of testdata/name with src and extracts its usage,
+// returning the decoded lines and the counts.
+func extractFixture(t *testing.T, src TranscriptSource, name string) ([]Line, Counts) {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var lines []Line
+	state, counts := &lineState{}, newCounts()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line, ok := src.Decode(sc.Bytes())
+		if !ok {
+			continue
+		}
+		lines = append(lines, line)
+		extractLine(line, state, counts)
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return lines, counts
+}
+
+func TestTranscripts_Files(t *testing.T) {
+	home := t.TempDir()
+
+	// One session each in transcripts/ and two projects/
+	for _, dir := range []string{"transcripts", filepath.Join("projects", "-proj-a"), filepath.Join("projects", "-proj-b")} {
+		os.MkdirAll(filepath.Join(home, ".claude", dir), 0o755)
+		writeJSONL(t, filepath.Join(home, ".claude", dir, "s.jsonl"), nil)
+	}
+
+	files := transcripts{DefaultSources, home, ProjectFilter{}}.files(time.Time{})
+	if len(files) != 3 {
+		t.Errorf("expected 3 files (transcripts + 2 projects), got %d: %v", len(files), files)
+	}
+	files = transcripts{DefaultSources, home, ProjectFilter{Path: "/proj/a"}}.files(time.Time{})
+	if len(files) != 1 || files[0].source != (ClaudeCodeSource{}) {
+		t.Errorf("project scope should read only -proj-a with the Claude Code source, got %v", files)
+	}
+}
+
+func TestCollector_Sources(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, ".claude", "transcripts")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeJSONL(t, filepath.Join(dir, "s.jsonl"), []string{
+		`{"type":"tool_use","tool_name":"Read","tool_input":{}}`,
+	})
+
+	data, err := (&Collector{HomeDir: home, Sources: []TranscriptSource{ClaudeCodeSource{}}}).Collect(ScopeAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Tools) != 0 {
+		t.Errorf("Tools = %+v, want none without the opencode source", data.Tools)
+	}
+}
@@ -0,0 +1,121 @@
+package usage
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"time"
+)
+
+// maxTailRead bounds how much of a single file is read per poll.
+const maxTailRead = 8 << 20
+
+// Tailer follows transcript files and extracts usage from lines appended since the last poll.
+type Tailer struct {
+	transcripts transcripts
+	cutoff      time.Time
+	offsets     map[string]int64      // Byte offset of the first unread line per file
+	states      map[string]*lineState // Extraction context per file
+}
+
+// NewTailer creates a Tailer over the transcripts of DefaultSources whose baseline
+// is the current end of every transcript file. Files created later are read from the start.
+func NewTailer(homeDir string, project ProjectFilter, cutoff time.Time) *Tailer {
+	return newTailer(transcripts{DefaultSources, homeDir, project}, cutoff)
+}
+
+func newTailer(ts transcripts, cutoff time.Time) *Tailer {
+	t := &Tailer{
+		transcripts: ts,
+		cutoff:      cutoff,
+		offsets:     make(map[string]int64),
+		states:      make(map[string]*lineState),
+	}
+	for _, f := range ts.files(time.Time{}) {
+		if info, err := os.Stat(f.path); err == nil {
+			t.offsets[f.path] = info.Size()
+		}
+	}
+	return t
+}
+
+// Poll reads complete lines appended since the previous poll and returns their counts.
+// A trailing partial line is left for the next poll.
+func (t *Tailer) Poll() Counts {
+	counts := newCounts()
+	for _, f := range t.transcripts.files(time.Time{}) {
+		t.readAppended(f, counts)
+	}
+	return counts
+}
+
+func (t *Tailer) readAppended(file transcriptFile, counts Counts) {
+	path := file.path
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	offset := t.offsets[path]
+	if info.Size() < offset {
+		// Truncated or replaced: skip what is there rather than recount it.
+		t.offsets[path] = info.Size()
+		return
+	}
+	if info.Size() == offset {
+		return
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	buf := make([]byte, min(info.Size()-offset, maxTailRead))
+	n, err := f.ReadAt(buf, offset)
+	if err != nil && err != io.EOF {
+		return
+	}
+	buf = buf[:n]
+
+	end := bytes.LastIndexByte(buf, '\n')
+	if end < 0 {
+		return // No complete line yet.
+	}
+	t.offsets[path] = offset + int64(end) + 1
+
+	state := t.states[path]
+	if state == nil {
+		state = &lineState{}
+		t.states[path] = state
+	}
+	hasCutoff := !t.cutoff.IsZero()
+	scratch := newCounts()
+	for _, raw := range bytes.Split(buf[:end], []byte("\n")) {
+		line, ok := file.source.Decode(raw)
+		if !ok {
+			continue
+		}
+		ts, dated := line.Timestamp, !line.Timestamp.IsZero()
+		if hasCutoff && dated && ts.Before(t.cutoff) {
+			continue
+		}
+		scratch.reset()
+		extractLine(line, state, scratch)
+		day := ""
+		if dated {
+			day = ts.Local().Format(dayLayout)
+		}
+		project := state.project(path)
+		counts.addLine(scratch, project, day)
+		if n := scratch.messageCount(); n > 0 {
+			session := newSession(path, project)
+			session.Messages = n
+			if dated {
+				session.Start, session.End = ts, ts
+				session.Hours[ts.Local().Hour()] = n
+			}
+			counts.addSession(session)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTailer_ReadsAppendedLines(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, ".claude", "projects", "-project-a")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "session.jsonl")
+	writeJSONL(t, path, []string{
+		`{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Read","input":{}}]}}`,
+	})
+
+	tailer := NewTailer(home, ProjectFilter{}, time.Time{})
+	if got := tailer.Poll(); !got.Empty() {
+		t.Fatalf("existing lines should be part of the baseline, got %+v", got)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(`{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Bash","input":{}},{"type":"tool_use","name":"Skill","input":{"skill":"commit"}}]}}` + "\n" + `{"type":"assistant","mess`); err != nil {
+		t.Fatal(err)
+	}
+
+	got := tailer.Poll()
+	if got.Tools["Bash"] != 1 || got.Tools["Skill"] != 1 || got.Skills["commit"] != 1 {
+		t.Errorf("Poll() = %+v, want Bash, Skill and commit once", got)
+	}
+
+	// Completing the partial line makes it visible to the next poll.
+	if _, err := f.WriteString(`age":{"content":[{"type":"tool_use","name":"Task","input":{"subagent_type":"reviewer"}}]}}` + "\n"); err != nil {
+		t.Fatal(err)
+	}
+	got = tailer.Poll()
+	if got.Agents["reviewer"] != 1 || got.Tools["Bash"] != 0 {
+		t.Errorf("Poll() = %+v, want only reviewer", got)
+	}
+
+	// New files are read from the start.
+	writeJSONL(t, filepath.Join(dir, "new.jsonl"), []string{
+		`{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Grep","input":{}}]}}`,
+	})
+	if got := tailer.Poll(); got.Tools["Grep"] != 1 {
+		t.Errorf("Poll() = %+v, want Grep from new file", got)
+	}
+}
+
+func TestUsageData_AddReportsPromotions(t *testing.T) {
+	data := &UsageData{counts: Counts{
+		Agents: map[string]int{"planner": 100, "reviewer": 1},
+		Tools:  map[string]int{},
+		Skills: map[string]int{},
+	}}
+	data.rank()
+	before := data.Agents[1].Grade
+
+	promotions := data.Add(Counts{Agents: map[string]int{"reviewer": 60}})
+	if len(promotions) != 1 || promotions[0].Name != "reviewer" || promotions[0].From != before {
+		t.Fatalf("promotions = %+v, want reviewer promoted from %s", promotions, before)
+	}
+	if data.Agents[1].Count != 61 {
+		t.Errorf("reviewer count = %d, want 61", data.Agents[1].Count)
+	}
+}
@@ -0,0 +1,159 @@
+package usage
+
+import (
+	"encoding/json"
+	"maps"
+	"path/filepath"
+)
+
+// maxPendingTasks bounds the Task calls tracked per transcript for agent attribution.
+// Calls that never get a result (e.g. an interrupted session) are dropped past it.
+const maxPendingTasks = 32
+
+// TokenUsage counts the tokens of each kind reported by the API.
+type TokenUsage struct {
+	Input         int64 `json:"input,omitempty"`
+	Output        int64 `json:"output,omitempty"`
+	CacheRead     int64 `json:"cache_read,omitempty"`
+	CacheCreation int64 `json:"cache_creation,omitempty"`
+}
+
+// Total returns the sum of all token kinds.
+func (u TokenUsage) Total() int64 {
+	return u.Input + u.Output + u.CacheRead + u.CacheCreation
+}
+
+func (u TokenUsage) plus(o TokenUsage) TokenUsage {
+	return TokenUsage{
+		Input:         u.Input + o.Input,
+		Output:        u.Output + o.Output,
+		CacheRead:     u.CacheRead + o.CacheRead,
+		CacheCreation: u.CacheCreation + o.CacheCreation,
+	}
+}
+
+// ModelTokens holds token usage per model.
+type ModelTokens map[string]TokenUsage
+
+// Total returns the usage summed over all models.
+func (m ModelTokens) Total() TokenUsage {
+	var total TokenUsage
+	for _, u := range m {
+		total = total.plus(u)
+	}
+	return total
+}
+
+func (m ModelTokens) merge(other ModelTokens) {
+	for model, u := range other {
+		m[model] = m[model].plus(u)
+	}
+}
+
+// mergeGrouped adds other's per-group token usage into dst.
+func mergeGrouped(dst, other map[string]ModelTokens) {
+	for group, tokens := range other {
+		addGrouped(dst, group, tokens)
+	}
+}
+
+// addGrouped adds tokens to the group key of dst.
+func addGrouped(dst map[string]ModelTokens, group string, tokens ModelTokens) {
+	if len(tokens) == 0 {
+		return
+	}
+	if dst[group] == nil {
+		dst[group] = ModelTokens{}
+	}
+	dst[group].merge(tokens)
+}
+
+// lineState carries context across the lines of one transcript.
+type lineState struct {
+	Cwd         string            `json:"cwd,omitempty"`          // Working directory of the session
+	LastMessage string            `json:"last_message,omitempty"` // Last assistant message counted for tokens
+	Tasks       map[string]string `json:"tasks,omitempty"`        // Running Task tool_use ID -> agent name
+	LastReply   string            `json:"last_reply,omitempty"`   // Last assistant message counted as a session message
+	Calls       map[string]string `json:"calls,omitempty"`        // Tool_use ID -> tool name awaiting its result
+}
+
+func (s *lineState) clone() *lineState {
+	c := *s
+	c.Tasks = maps.Clone(s.Tasks)
+	c.Calls = maps.Clone(s.Calls)
+	return &c
+}
+
+// project returns the project a transcript belongs to: the base name of the
+// session's working directory, or of the transcript's directory if unknown.
+func (s *lineState) project(path string) string {
+	if s.Cwd != "" {
+		return filepath.Base(s.Cwd)
+	}
+	return filepath.Base(filepath.Dir(path))
+}
+
+// extractTokens adds the token usage of an assistant line to counts, tracking
+// Task calls in state so that sidechain usage can be attributed to a subagent.
+// Claude Code writes one line per content block, repeating the message usage, so
+// only the first line of each message is counted.
+func extractTokens(line Line, state *lineState, counts Counts) {
+	if !line.Sidechain {
+		trackTasks(line, state)
+	}
+
+	if line.Role != "assistant" || line.Usage.Total() == 0 || line.Model == "" || line.Model == "<synthetic>" {
+		return
+	}
+	if id := line.MessageID; id != "" {
+		if id == state.LastMessage {
+			return
+		}
+		state.LastMessage = id
+	}
+	tokens := ModelTokens{line.Model: line.Usage}
+	counts.Tokens.merge(tokens)
+
+	// Sidechain usage is attributable only while exactly one subagent is running.
+	if line.Sidechain && len(state.Tasks) == 1 {
+		for _, agent := range state.Tasks {
+			addGrouped(counts.AgentTokens, agent, tokens)
+		}
+	}
+}
+
+// trackTasks records Task calls started on the line and forgets those whose result arrived.
+func trackTasks(line Line, state *lineState) {
+	for _, call := range line.Calls {
+		if call.Name != "Task" || call.ID == "" {
+			continue
+		}
+		var input agentInput
+		if err := json.Unmarshal(call.Input, &input); err != nil || input.SubagentType == "" {
+			continue
+		}
+		name := resolveAgentName(input)
+		if name == "" {
+			name = input.SubagentType
+		}
+		if state.Tasks == nil || len(state.Tasks) >= maxPendingTasks {
+			state.Tasks = make(map[string]string)
+		}
+		state.Tasks[call.ID] = name
+	}
+	for _, r := range line.Results {
+		delete(state.Tasks, r.CallID)
+	}
+	if len(state.Tasks) == 0 {
+		state.Tasks = nil
+	}
+}
+
+// perModel splits per-model usage into single-model groups for ranking.
+func perModel(tokens ModelTokens) map[string]ModelTokens {
+	groups := make(map[string]ModelTokens, len(tokens))
+	for model, u := range tokens {
+		groups[model] = ModelTokens{model: u}
+	}
+	return groups
+}
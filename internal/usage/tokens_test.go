@@ -0,0 +1,116 @@
+package usage
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// assistantUsage builds a Claude Code assistant line carrying token usage.
+func assistantUsage(id, model, ts string, sidechain bool, in, out int) string {
+	side := "false"
+	if sidechain {
+		side = "true"
+	}
+	return `{"type":"assistant","cwd":"/home/me/code/ccfg","isSidechain":` + side + `,"timestamp":"` + ts +
+		`","message":{"id":"` + id + `","model":"` + model + `","content":[{"type":"text","text":"hi"}],` +
+		`"usage":{"input_tokens":` + strconv.Itoa(in) + `,"output_tokens":` + strconv.Itoa(out) + `,"cache_read_input_tokens":100,"cache_creation_input_tokens":0}}}`
+}
+
+func TestCollect_TokensByModelProjectDayAndAgent(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, ".claude", "projects", "-home-me-code-ccfg")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	ts := time.Now().UTC().Format(time.RFC3339)
+	writeJSONL(t, filepath.Join(dir, "s.jsonl"), []string{
+		// Two lines of the same message repeat its usage; it counts once.
+		assistantUsage("msg_1", "claude-opus-4-1-20250805", ts, false, 1000, 500),
+		assistantUsage("msg_1", "claude-opus-4-1-20250805", ts, false, 1000, 500),
+		// Start a reviewer subagent, then account its sidechain usage to it.
+		`{"type":"assistant","timestamp":"` + ts + `","message":{"content":[{"type":"tool_use","id":"toolu_1","name":"Task","input":{"subagent_type":"reviewer"}}]}}`,
+		assistantUsage("msg_2", "claude-sonnet-4-5-20250929", ts, true, 2000, 1000),
+		`{"type":"user","timestamp":"` + ts + `","message":{"content":[{"type":"tool_result","tool_use_id":"toolu_1","content":"done"}]}}`,
+		// After the result, sidechain usage is no longer attributed.
+		assistantUsage("msg_3", "claude-sonnet-4-5-20250929", ts, true, 10, 10),
+		// Synthetic messages carry no real usage.
+		assistantUsage("msg_4", "<synthetic>", ts, false, 5, 5),
+	})
+
+	data, err := (&Collector{HomeDir: home}).Collect(ScopeAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byName := func(g TokenGroup) map[string]RankEntry {
+		out := make(map[string]RankEntry)
+		for _, e := range data.Tokens[g] {
+			out[e.Name] = e
+		}
+		return out
+	}
+
+	models := byName(TokensByModel)
+	if got := models["claude-opus-4-1-20250805"].Count; got != 1600 {
+		t.Errorf("opus tokens = %d, want 1600 (message counted once)", got)
+	}
+	if got := models["claude-sonnet-4-5-20250929"].Count; got != 3220 {
+		t.Errorf("sonnet tokens = %d, want 3220", got)
+	}
+	if _, ok := models["<synthetic>"]; ok {
+		t.Error("synthetic model should be ignored")
+	}
+
+	// opus: (1000*15 + 500*75 + 100*1.5) / 1e6
+	if got, want := models["claude-opus-4-1-20250805"].Cost, 0.05265; math.Abs(got-want) > 1e-9 {
+		t.Errorf("opus cost = %v, want %v", got, want)
+	}
+
+	if got := byName(TokensByProject)["ccfg"].Count; got != 4820 {
+		t.Errorf("project tokens = %d, want 4820", got)
+	}
+	today := time.Now().Format("2006-01-02")
+	if got := byName(TokensByDay)[today].Count; got != 4820 {
+		t.Errorf("day tokens = %d, want 4820", got)
+	}
+	agents := byName(TokensByAgent)
+	if len(agents) != 1 || agents["reviewer"].Count != 3100 {
+		t.Errorf("agent tokens = %+v, want reviewer=3100 only", agents)
+	}
+
+	if data.TokenTotal.Total() != 4820 {
+		t.Errorf("TokenTotal = %d, want 4820", data.TokenTotal.Total())
+	}
+}
+
+func TestPriceTable_LongestPrefixAndOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.json")
+	if err := os.WriteFile(path, []byte(`{"claude-opus-4-1":{"input":1,"output":2}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	prices, err := LoadPrices(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p, _ := prices.Lookup("claude-opus-4-1-20250805"); p.Input != 1 {
+		t.Errorf("opus-4-1 input = %v, want the override 1", p.Input)
+	}
+	if p, _ := prices.Lookup("claude-opus-4-20250514"); p.Input != 15 {
+		t.Errorf("opus-4 input = %v, want default 15", p.Input)
+	}
+	if p, _ := prices.Lookup("claude-opus-4-5-20251101"); p.Input != 5 {
+		t.Errorf("opus-4-5 input = %v, want 5", p.Input)
+	}
+	if _, ok := prices.Lookup("gpt-5"); ok {
+		t.Error("unknown model should not match")
+	}
+
+	if _, err := LoadPrices(filepath.Join(t.TempDir(), "missing.json")); err != nil {
+		t.Errorf("missing price file should not be an error: %v", err)
+	}
+}
@@ -1,13 +1,10 @@
 package usage
 
 import (
-	"bufio"
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
-	"time"
 )
 
 // sessionMeta parses only the required fields from a session-meta JSON file.
@@ -16,24 +13,9 @@ type sessionMeta struct {
 	ToolCounts  map[string]int `json:"tool_counts"`
 }
 
-// collectTools tallies tool invocation counts from both session-meta and transcripts.
-func collectTools(homeDir, projectFilter string, cutoff time.Time) (map[string]int, error) {
-	counts := make(map[string]int)
-
-	// 1) Collect from session-meta
-	if err := collectToolsFromSessionMeta(homeDir, projectFilter, cutoff, counts); err != nil {
-		return nil, err
-	}
-
-	// 2) Collect from transcripts (a single line may contain multiple tool_use blocks)
-	if err := collectToolsFromTranscripts(homeDir, projectFilter, cutoff, counts); err != nil {
-		return nil, err
-	}
-
-	return counts, nil
-}
-
-func collectToolsFromSessionMeta(homeDir, projectFilter string, cutoff time.Time, counts map[string]int) error {
+// collectToolsFromSessionMeta adds per-session tool counts recorded in session-meta
+// files last modified within w.
+func collectToolsFromSessionMeta(homeDir string, project ProjectFilter, w window, counts map[string]int) error {
 	dir := filepath.Join(homeDir, ".claude", "usage-data", "session-meta")
 	entries, err := os.ReadDir(dir)
 	if err != nil {
@@ -43,14 +25,14 @@ func collectToolsFromSessionMeta(homeDir, projectFilter string, cutoff time.Time
 		return fmt.Errorf("failed to read session-meta directory: %w", err)
 	}
 
-	hasCutoff := !cutoff.IsZero()
 	for _, entry := range entries {
 		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
 			continue
 		}
-		// ModTime optimization: skip session-meta files older than cutoff.
-		if hasCutoff {
-			if info, err := entry.Info(); err == nil && info.ModTime().Before(cutoff) {
+		// Session-meta files carry no timestamps; their modification time places them in w.
+		if !w.since.IsZero() || !w.until.IsZero() {
+			info, err := entry.Info()
+			if err != nil || !w.contains(info.ModTime()) {
 				continue
 			}
 		}
@@ -62,7 +44,7 @@ func collectToolsFromSessionMeta(homeDir, projectFilter string, cutoff time.Time
 		if err := json.Unmarshal(data, &meta); err != nil {
 			continue
 		}
-		if projectFilter != "" && meta.ProjectPath != projectFilter {
+		if !project.Matches(meta.ProjectPath) {
 			continue
 		}
 		for tool, count := range meta.ToolCounts {
@@ -71,86 +53,3 @@ func collectToolsFromSessionMeta(homeDir, projectFilter string, cutoff time.Time
 	}
 	return nil
 }
-
-// collectToolsFromTranscripts extracts tool usage directly from transcript files.
-// Uses a dedicated scanner instead of extractFunc because a single line may contain multiple tool_use blocks.
-func collectToolsFromTranscripts(homeDir, projectFilter string, cutoff time.Time, counts map[string]int) error {
-	dirs := transcriptDirs(homeDir, projectFilter)
-	hasCutoff := !cutoff.IsZero()
-	for _, dir := range dirs {
-		entries, err := os.ReadDir(dir)
-		if err != nil {
-			continue
-		}
-		for _, entry := range entries {
-			if entry.IsDir() || filepath.Ext(entry.Name()) != ".jsonl" {
-				continue
-			}
-			if hasCutoff {
-				if info, err := entry.Info(); err == nil && info.ModTime().Before(cutoff) {
-					continue
-				}
-			}
-			scanFileMultiTool(filepath.Join(dir, entry.Name()), counts, cutoff)
-		}
-	}
-	return nil
-}
-
-func scanFileMultiTool(path string, counts map[string]int, cutoff time.Time) {
-	f, err := os.Open(path)
-	if err != nil {
-		return
-	}
-	defer f.Close()
-
-	hasCutoff := !cutoff.IsZero()
-	scanner := bufio.NewScanner(f)
-	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
-	for scanner.Scan() {
-		line := scanner.Bytes()
-		if hasCutoff {
-			if ts, ok := extractTimestamp(line); ok && ts.Before(cutoff) {
-				continue
-			}
-		}
-		extractToolsFromLine(line, counts)
-	}
-}
-
-// extractToolsFromLine extracts all tool names from a single line and adds them to counts.
-func extractToolsFromLine(line []byte, counts map[string]int) {
-	if !bytes.Contains(line, []byte(`"tool_use"`)) {
-		return
-	}
-
-	// opencode format: {"type":"tool_use", "tool_name":"Read"}
-	if bytes.Contains(line, []byte(`"tool_name"`)) {
-		var ol opencodeLine
-		if err := json.Unmarshal(line, &ol); err == nil && ol.ToolName != "" {
-			counts[ol.ToolName]++
-			return
-		}
-	}
-
-	// Claude Code format: tool_use blocks inside an assistant message
-	if !bytes.Contains(line, []byte(`"assistant"`)) {
-		return
-	}
-
-	var cl claudeCodeLine
-	if err := json.Unmarshal(line, &cl); err != nil || cl.Type != "assistant" {
-		return
-	}
-
-	var blocks []contentBlock
-	if err := json.Unmarshal(cl.Message.Content, &blocks); err != nil {
-		return
-	}
-
-	for _, block := range blocks {
-		if block.Type == "tool_use" && block.Name != "" {
-			counts[block.Name]++
-		}
-	}
-}
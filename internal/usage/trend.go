@@ -0,0 +1,141 @@
+package usage
+
+import "time"
+
+// countsOf selects the per-name counts a ranking is built from.
+type countsOf func(Counts) map[string]int
+
+// Change returns the relative change of Count against Prior, e.g. 0.42 for +42%.
+// ok is false when nothing was counted in the prior period.
+func (e RankEntry) Change() (change float64, ok bool) {
+	if e.Prior == 0 {
+		return 0, false
+	}
+	return float64(e.Count-e.Prior) / float64(e.Prior), true
+}
+
+// priorWindow returns the period of the same length that ends where the one
+// starting at since begins. It is zero if since is (all time has no prior period).
+func priorWindow(since, now time.Time) window {
+	if since.IsZero() {
+		return window{}
+	}
+	return window{since: since.Add(-now.Sub(since)), until: since}
+}
+
+// days lists the local days of the trend, from the start of the period (or the
+// first day with usage for all time) through today or the last day of the range.
+func (d *UsageData) days() []string {
+	start := d.since
+	if start.IsZero() {
+		for key := range d.counts.Daily {
+			day, err := time.ParseInLocation(dayLayout, key, time.Local)
+			if err == nil && (start.IsZero() || day.Before(start)) {
+				start = day
+			}
+		}
+		if start.IsZero() {
+			return nil
+		}
+	}
+	end := time.Now()
+	if !d.until.IsZero() && d.until.Before(end) {
+		end = d.until.Add(-time.Nanosecond)
+	}
+	var days []string
+	for day := startOfDay(start); !day.After(end); day = day.AddDate(0, 0, 1) {
+		days = append(days, day.Format(dayLayout))
+	}
+	return days
+}
+
+// trend fills the daily series and prior-period counts of entries, selected by of.
+// Session-meta tool counts carry no timestamps, so they appear in the totals but not in the series.
+func (d *UsageData) trend(entries []RankEntry, of countsOf) []RankEntry {
+	days := d.days()
+	index := make(map[string]int, len(entries))
+	for i := range entries {
+		index[entries[i].Name] = i
+		entries[i].Daily = make([]int, len(days))
+	}
+	for i, day := range days {
+		counts, ok := d.counts.Daily[day]
+		if !ok {
+			continue
+		}
+		for name, n := range of(counts) {
+			if j, ok := index[name]; ok {
+				entries[j].Daily[i] += n
+			}
+		}
+	}
+	if d.HasPrior {
+		prior := of(d.prior)
+		for i := range entries {
+			entries[i].Prior = prior[entries[i].Name]
+		}
+	}
+	return entries
+}
+
+// rankTrend ranks the counts selected by of and attaches their trend.
+func (d *UsageData) rankTrend(of countsOf) []RankEntry {
+	return d.trend(d.grading.Rank(of(d.counts)), of)
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// The selectors below normalize names since daily and prior counts are stored raw.
+
+func agentCounts(c Counts) map[string]int   { return normalizeCounts(c.Agents) }
+func skillCounts(c Counts) map[string]int   { return normalizeCounts(c.Skills) }
+func commandCounts(c Counts) map[string]int { return c.Commands }
+
+func builtinToolCounts(c Counts) map[string]int {
+	builtin, _, _ := splitMCP(normalizeCounts(c.Tools), nil)
+	return builtin
+}
+
+// mcpServerCounts counts calls per MCP server, listing configured servers without calls.
+func (d *UsageData) mcpServerCounts(c Counts) map[string]int {
+	_, servers, _ := splitMCP(c.Tools, d.mcpServers)
+	return servers
+}
+
+func mcpToolCounts(server string) countsOf {
+	return func(c Counts) map[string]int {
+		_, _, perServer := splitMCP(c.Tools, nil)
+		return perServer[server]
+	}
+}
+
+// tokenGroups returns the token usage of c broken down by g.
+func tokenGroups(c Counts, g TokenGroup) map[string]ModelTokens {
+	switch g {
+	case TokensByProject:
+		return c.ProjectTokens
+	case TokensByDay:
+		return c.DayTokens
+	case TokensByAgent:
+		return c.AgentTokens
+	default:
+		return perModel(c.Tokens)
+	}
+}
+
+// tokenCounts selects total tokens per group of g.
+func tokenCounts(g TokenGroup) countsOf {
+	return func(c Counts) map[string]int {
+		groups := tokenGroups(c, g)
+		totals := make(map[string]int, len(groups))
+		for name, tokens := range groups {
+			if n := tokens.Total().Total(); n > 0 {
+				totals[name] = int(n)
+			}
+		}
+		return totals
+	}
+}
@@ -0,0 +1,118 @@
+package usage
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCollect_TrendDailyAndPrior(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, ".claude", "projects", "-project-a")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	tool := func(name string, ago time.Duration) string {
+		ts := time.Now().Add(-ago).UTC().Format(time.RFC3339)
+		return `{"type":"assistant","timestamp":"` + ts + `","message":{"content":[{"type":"tool_use","name":"` + name + `","input":{}}]}}`
+	}
+	day := 24 * time.Hour
+	writeJSONL(t, filepath.Join(dir, "s.jsonl"), []string{
+		// Prior 7d: [now-14d, now-7d).
+		tool("Read", 9*day), tool("Read", 9*day), tool("Read", 10*day), tool("Bash", 10*day),
+		// Current 7d.
+		tool("Read", 2*day), tool("Read", 0), tool("Grep", 0),
+	})
+
+	data, err := (&Collector{HomeDir: home, Period: PeriodWeek}).Collect(ScopeAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !data.HasPrior {
+		t.Fatal("HasPrior = false for a 7d period")
+	}
+	byName := make(map[string]RankEntry)
+	for _, e := range data.Tools {
+		byName[e.Name] = e
+	}
+	if _, ok := byName["Bash"]; ok {
+		t.Error("Bash used only in the prior period should not be ranked")
+	}
+
+	read := byName["Read"]
+	if read.Count != 2 || read.Prior != 3 {
+		t.Fatalf("Read count/prior = %d/%d, want 2/3", read.Count, read.Prior)
+	}
+	if change, ok := read.Change(); !ok || math.Abs(change-(-1.0/3)) > 1e-9 {
+		t.Errorf("Read change = %v, %v; want -33%%", change, ok)
+	}
+	// The series runs from the local day 7 days ago through today.
+	if len(read.Daily) != 8 {
+		t.Fatalf("len(Daily) = %d, want 8", len(read.Daily))
+	}
+	if read.Daily[7] != 1 || read.Daily[5] != 1 {
+		t.Errorf("Daily = %v, want one call today and one 2 days ago", read.Daily)
+	}
+
+	if _, ok := byName["Grep"].Change(); ok {
+		t.Error("Grep has no prior usage; Change should report !ok")
+	}
+}
+
+func TestCollect_AllTimeHasNoPrior(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, ".claude", "projects", "-project-a")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	ts := time.Now().Add(-3 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	writeJSONL(t, filepath.Join(dir, "s.jsonl"), []string{
+		`{"type":"assistant","timestamp":"` + ts + `","message":{"content":[{"type":"tool_use","name":"Read","input":{}}]}}`,
+	})
+
+	data, err := (&Collector{HomeDir: home}).Collect(ScopeAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.HasPrior {
+		t.Error("HasPrior = true for all time")
+	}
+	// All time starts the series at the first day with usage.
+	if got := data.Tools[0].Daily; len(got) != 4 || got[0] != 1 {
+		t.Errorf("Daily = %v, want 4 days starting with the call", got)
+	}
+}
+
+func TestWindow_Bounds(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 20, 0, 0, time.UTC)
+	since := now.Add(-7 * 24 * time.Hour)
+	prior := priorWindow(since, now)
+	if !prior.since.Equal(now.Add(-14*24*time.Hour)) || !prior.until.Equal(since) {
+		t.Fatalf("priorWindow = %v..%v", prior.since, prior.until)
+	}
+	if !prior.contains(prior.since) || prior.contains(since) {
+		t.Error("window should include since and exclude until")
+	}
+	if (priorWindow(time.Time{}, now) != window{}) {
+		t.Error("all time should have an empty prior window")
+	}
+
+	// A bucket belongs to the window its start falls in.
+	entry := &fileIndex{Buckets: map[string]Counts{
+		bucketKey(since.Add(10 * time.Minute)): {Tools: map[string]int{"Read": 1}},
+		bucketKey(since.Add(-5 * time.Minute)): {Tools: map[string]int{"Read": 10}},
+		bucketKey(since.Add(-2 * time.Hour)):   {Tools: map[string]int{"Read": 1000}},
+		"":                                     {Tools: map[string]int{"Read": 100}},
+	}}
+	cur, old := newCounts(), newCounts()
+	entry.sumRange(window{since: since}, "p", cur)
+	entry.sumRange(prior, "p", old)
+	if cur.Tools["Read"] != 101 {
+		t.Errorf("current Read = %d, want 101 (later bucket and undated lines)", cur.Tools["Read"])
+	}
+	if old.Tools["Read"] != 1010 {
+		t.Errorf("prior Read = %d, want 1010 (straddling and earlier buckets)", old.Tools["Read"])
+	}
+}
@@ -46,8 +46,31 @@ const (
 	RankAgents RankCategory = iota
 	RankTools
 	RankSkills
+	RankTokens
+	RankMCP
+	RankCommands
+	RankSessions
+	RankUnused
+	RankErrors
+	RankHotspots
+
+	rankCategoryCount = iota // number of RankCategory values (must remain last)
 )
 
+// RankCategories lists every ranking category in tab order.
+func RankCategories() []RankCategory {
+	cats := make([]RankCategory, rankCategoryCount)
+	for i := range cats {
+		cats[i] = RankCategory(i)
+	}
+	return cats
+}
+
+// Next returns the next category in tab order, wrapping around.
+func (r RankCategory) Next() RankCategory {
+	return (r + 1) % rankCategoryCount
+}
+
 func (r RankCategory) String() string {
 	switch r {
 	case RankAgents:
@@ -56,26 +79,108 @@ func (r RankCategory) String() string {
 		return "Tools"
 	case RankSkills:
 		return "Skills"
+	case RankTokens:
+		return "Tokens"
+	case RankMCP:
+		return "MCP"
+	case RankCommands:
+		return "Commands"
+	case RankSessions:
+		return "Sessions"
+	case RankUnused:
+		return "Unused"
+	case RankErrors:
+		return "Errors"
+	case RankHotspots:
+		return "Hotspots"
 	default:
 		return "Unknown"
 	}
 }
 
+// TokenGroup selects how token usage is broken down in the Tokens ranking.
+type TokenGroup int
+
+const (
+	TokensByModel TokenGroup = iota
+	TokensByProject
+	TokensByDay
+	TokensByAgent
+
+	tokenGroupCount = iota // number of TokenGroup values (must remain last)
+)
+
+func (g TokenGroup) String() string {
+	switch g {
+	case TokensByProject:
+		return "Project"
+	case TokensByDay:
+		return "Day"
+	case TokensByAgent:
+		return "Agent"
+	default:
+		return "Model"
+	}
+}
+
+// Next returns the next grouping in the cycle: Model → Project → Day → Agent → Model.
+func (g TokenGroup) Next() TokenGroup {
+	return (g + 1) % tokenGroupCount
+}
+
+// SessionGroup selects how sessions are broken down in the Sessions ranking.
+type SessionGroup int
+
+const (
+	SessionsByProject SessionGroup = iota // Sessions per project
+	SessionsByDay                         // Sessions per local start day
+	SessionsByHour                        // Messages per local hour of day
+
+	sessionGroupCount = iota // number of SessionGroup values (must remain last)
+)
+
+func (g SessionGroup) String() string {
+	switch g {
+	case SessionsByDay:
+		return "Day"
+	case SessionsByHour:
+		return "Hour"
+	default:
+		return "Project"
+	}
+}
+
+// Next returns the next grouping in the cycle: Project → Day → Hour → Project.
+func (g SessionGroup) Next() SessionGroup {
+	return (g + 1) % sessionGroupCount
+}
+
 // DataScope represents the data collection scope.
 type DataScope int
 
 const (
-	ScopeAll     DataScope = iota // All projects
-	ScopeProject                  // Current project only
+	ScopeAll         DataScope = iota // All projects
+	ScopeProject                      // Current project only
+	ScopeProjectTree                  // Current project, its subdirectories and git worktrees
+
+	dataScopeCount = iota // number of DataScope values (must remain last)
 )
 
 func (d DataScope) String() string {
-	if d == ScopeProject {
+	switch d {
+	case ScopeProject:
 		return "Project"
+	case ScopeProjectTree:
+		return "Project+"
 	}
 	return "All"
 }
 
+// Next returns the next scope in the cycle: All → Project → Project+ → All.
+func (d DataScope) Next() DataScope {
+	return (d + 1) % dataScopeCount
+}
+
 // TimePeriod represents a time range filter for usage data.
 type TimePeriod int
 
@@ -126,12 +231,143 @@ type RankEntry struct {
 	Name     string
 	Count    int
 	Grade    Grade
-	LogScore float64
+	Score    float64     // Position in [0, 1] under the grading model, drawn as the bar
+	Cost     float64     // Estimated USD cost (token rankings only)
+	Custom   bool        // Defined under .claude/commands (command rankings only)
+	Daily    []int       // Count per local day of the period, oldest first
+	Prior    int         // Count in the equally long period before this one
+	Session  *Session    // Session behind the row (session lists only)
+	Item     *ConfigItem // Configured item behind the row (unused report only)
+	LastUsed time.Time   // Last use at any time, zero if never (unused report only)
+	Calls    int         // Calls of the tool; Count holds the failed ones (error ranking only)
 }
 
 // UsageData holds the collected usage data for all categories.
 type UsageData struct {
-	Agents []RankEntry
-	Tools  []RankEntry
-	Skills []RankEntry
+	Agents   []RankEntry
+	Tools    []RankEntry
+	Skills   []RankEntry
+	Commands []RankEntry
+	Tokens   map[TokenGroup][]RankEntry // Token totals per grouping, graded like invocations
+
+	MCP      []RankEntry            // MCP calls per server, including configured servers never called
+	MCPTools map[string][]RankEntry // Per-server tool rankings for drill-down, keyed by server
+
+	Hotspots     map[HotspotGroup][]RankEntry // Bash command prefixes, project files read and edited, fetched domains
+	SessionRanks map[SessionGroup][]RankEntry // Sessions per project or day, messages per hour of day
+	SessionStats SessionStats                 // Totals over the sessions active in the period
+
+	Unused []RankEntry // Configured items used at most LowUsage times in the period
+
+	Achievements []Achievement // Milestones reached over all-time usage in the scope, regardless of the period
+
+	ToolErrors    []RankEntry            // Failed calls per tool, MCP tools as "server/tool"
+	ErrorSnippets map[string][]RankEntry // Most common error messages per ToolErrors name
+
+	TokenTotal TokenUsage // Tokens used in the period
+	CostTotal  float64    // Estimated USD cost of TokenTotal
+	HasPrior   bool       // Whether entries carry counts for the prior period (not for all time)
+
+	counts         Counts              // Normalized counts backing the rankings
+	prior          Counts              // Normalized counts of the prior period, if HasPrior
+	since          time.Time           // Start of the period (zero for all time)
+	until          time.Time           // End of an explicit range (zero for up to now)
+	prices         PriceTable          // Prices used for cost estimates
+	grading        Grading             // How entries are graded
+	projectPath    string              // Current project, which file hotspots are limited to
+	mcpServers     []string            // Configured MCP servers
+	customCommands []string            // Commands defined under .claude/commands
+	config         []ConfigItem        // Configured items checked for the unused report
+	lastUsed       lastUse             // Last use per item at any time
+	trophies       *achievementTracker // All-time progress toward achievements
+}
+
+// Counts holds raw invocation counts per category and token usage.
+type Counts struct {
+	Agents      map[string]int         `json:"agents,omitempty"`
+	Tools       map[string]int         `json:"tools,omitempty"`
+	Skills      map[string]int         `json:"skills,omitempty"`
+	Commands    map[string]int         `json:"commands,omitempty"`
+	Tokens      ModelTokens            `json:"tokens,omitempty"`       // Per model
+	AgentTokens map[string]ModelTokens `json:"agent_tokens,omitempty"` // Per subagent, where attributable
+	Messages    map[string]int         `json:"messages,omitempty"`     // User prompts and assistant replies per role
+	Errors      map[string]int         `json:"errors,omitempty"`       // Failed tool calls per tool
+
+	ErrorSnippets map[string]map[string]int `json:"error_snippets,omitempty"` // Error message counts per tool
+
+	BashCommands map[string]int `json:"bash_commands,omitempty"` // Calls per normalized command prefix, e.g. "go test"
+	FileReads    map[string]int `json:"file_reads,omitempty"`    // Read calls per file path
+	FileEdits    map[string]int `json:"file_edits,omitempty"`    // Edit, MultiEdit, Write and NotebookEdit calls per file path
+	WebDomains   map[string]int `json:"web_domains,omitempty"`   // WebFetch calls per domain
+
+	// Filled when lines are attributed to a transcript and a time, not stored per bucket.
+	ProjectTokens map[string]ModelTokens `json:"-"` // Per project
+	DayTokens     map[string]ModelTokens `json:"-"` // Per local day (YYYY-MM-DD)
+	Daily         map[string]Counts      `json:"-"` // Per local day (YYYY-MM-DD)
+	Sessions      map[string]Session     `json:"-"` // Per transcript path
+}
+
+// newCounts returns Counts with all maps allocated.
+func newCounts() Counts {
+	return Counts{
+		Agents:        map[string]int{},
+		Tools:         map[string]int{},
+		Skills:        map[string]int{},
+		Commands:      map[string]int{},
+		Messages:      map[string]int{},
+		Errors:        map[string]int{},
+		ErrorSnippets: map[string]map[string]int{},
+		BashCommands:  map[string]int{},
+		FileReads:     map[string]int{},
+		FileEdits:     map[string]int{},
+		WebDomains:    map[string]int{},
+		Tokens:        ModelTokens{},
+		AgentTokens:   map[string]ModelTokens{},
+		ProjectTokens: map[string]ModelTokens{},
+		DayTokens:     map[string]ModelTokens{},
+		Daily:         map[string]Counts{},
+		Sessions:      map[string]Session{},
+	}
+}
+
+// Empty reports whether no invocations, tokens, messages or errors were counted.
+func (c Counts) Empty() bool {
+	return len(c.Agents) == 0 && len(c.Tools) == 0 && len(c.Skills) == 0 && len(c.Commands) == 0 &&
+		len(c.Tokens) == 0 && len(c.Messages) == 0 && len(c.Errors) == 0
+}
+
+// reset empties every map so the Counts can be reused.
+func (c Counts) reset() {
+	clear(c.Messages)
+	clear(c.Errors)
+	clear(c.ErrorSnippets)
+	clear(c.BashCommands)
+	clear(c.FileReads)
+	clear(c.FileEdits)
+	clear(c.WebDomains)
+	clear(c.Agents)
+	clear(c.Tools)
+	clear(c.Skills)
+	clear(c.Commands)
+	clear(c.Tokens)
+	clear(c.AgentTokens)
+	clear(c.ProjectTokens)
+	clear(c.DayTokens)
+	clear(c.Daily)
+	clear(c.Sessions)
+}
+
+// addTokenDims attributes the token usage of c to project and, if known, to day.
+func (c Counts) addTokenDims(tokens ModelTokens, project, day string) {
+	addGrouped(c.ProjectTokens, project, tokens)
+	if day != "" {
+		addGrouped(c.DayTokens, day, tokens)
+	}
+}
+
+// Promotion records an entry whose grade improved after new usage was added.
+type Promotion struct {
+	Category RankCategory
+	Name     string
+	From, To Grade
 }
@@ -0,0 +1,134 @@
+package usage
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// LowUsage is the most uses in the period for which a configured item is reported as unused.
+const LowUsage = 2
+
+// ConfigItem is a configured agent, skill, command or MCP server.
+type ConfigItem struct {
+	Category RankCategory // RankAgents, RankSkills, RankCommands or RankMCP
+	Name     string       // Name as recorded in transcripts (e.g. "frontend:component" for a nested command)
+	Path     string       // File that defines the item
+}
+
+// lastUse records when each name was last used, per ranking category.
+type lastUse map[RankCategory]map[string]time.Time
+
+// note records a use of name at t if it is the latest seen.
+func (l lastUse) note(cat RankCategory, name string, t time.Time) {
+	if l[cat] == nil {
+		l[cat] = make(map[string]time.Time)
+	}
+	if t.After(l[cat][name]) {
+		l[cat][name] = t
+	}
+}
+
+// noteCounts records a use at t of every agent, skill, command and MCP server in c.
+func (l lastUse) noteCounts(c Counts, t time.Time) {
+	for name := range normalizeCounts(c.Agents) {
+		l.note(RankAgents, name, t)
+	}
+	for name := range normalizeCounts(c.Skills) {
+		l.note(RankSkills, name, t)
+	}
+	for name := range c.Commands {
+		l.note(RankCommands, name, t)
+	}
+	for name := range c.Tools {
+		if server, _, ok := splitMCPTool(name); ok {
+			l.note(RankMCP, server, t)
+		}
+	}
+}
+
+// timeBucket is the usage recorded in one transcript during one index bucket.
+type timeBucket struct {
+	at     time.Time // Start of the bucket
+	counts Counts
+}
+
+// collectHistory brings idx up to date with every transcript, regardless of the
+// period, and returns their buckets oldest first. Lines without a
+// timestamp are left out.
+func collectHistory(ctx context.Context, idx *usageIndex, ts transcripts) ([]timeBucket, error) {
+	files := ts.files(time.Time{})
+	pending, err := updateIndex(ctx, idx, files)
+	if err != nil {
+		return nil, err
+	}
+	var history []timeBucket
+	add := func(e *fileIndex) {
+		for key, bucket := range e.Buckets {
+			if at, err := time.Parse(bucketLayout, key); err == nil {
+				history = append(history, timeBucket{at, bucket})
+			}
+		}
+	}
+	for _, f := range files {
+		if entry := idx.Files[f.path]; entry != nil {
+			add(entry)
+		}
+	}
+	for _, partial := range pending {
+		add(partial)
+	}
+	sort.SliceStable(history, func(i, j int) bool { return history[i].at.Before(history[j].at) })
+	return history, nil
+}
+
+// lastUsedIn returns when each name was last used in history. Times are precise to bucketSize.
+func lastUsedIn(history []timeBucket) lastUse {
+	last := make(lastUse)
+	for _, b := range history {
+		last.noteCounts(b.counts, b.at)
+	}
+	return last
+}
+
+// rankUnused lists the configured items used at most LowUsage times in the period,
+// never-used and longest-idle first. Each entry keeps the grade and trend of the
+// item in its own ranking.
+func (d *UsageData) rankUnused() []RankEntry {
+	lists := map[RankCategory][]RankEntry{RankAgents: d.Agents, RankSkills: d.Skills, RankCommands: d.Commands, RankMCP: d.MCP}
+	var entries []RankEntry
+	for _, item := range d.config {
+		name := item.Name
+		if item.Category == RankMCP {
+			name = MCPToolServerName(name)
+		}
+		entry := RankEntry{Name: item.Name, Grade: GradeF}
+		for _, e := range lists[item.Category] {
+			if e.Name == name {
+				entry = e
+				entry.Name = item.Name
+				break
+			}
+		}
+		if entry.Count > LowUsage {
+			continue
+		}
+		entry.Item = &item
+		entry.LastUsed = d.lastUsed[item.Category][name]
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Count != b.Count {
+			return a.Count < b.Count
+		}
+		if !a.LastUsed.Equal(b.LastUsed) {
+			return a.LastUsed.Before(b.LastUsed)
+		}
+		if a.Item.Category != b.Item.Category {
+			return a.Item.Category < b.Item.Category
+		}
+		return a.Name < b.Name
+	})
+	return entries
+}
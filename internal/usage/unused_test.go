@@ -0,0 +1,69 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCollect_UnusedReport(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, ".claude", "projects", "-p")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	line := func(ago time.Duration, block string) string {
+		ts := time.Now().Add(-ago).UTC().Format(time.RFC3339)
+		return `{"type":"assistant","timestamp":"` + ts + `","message":{"content":[` + block + `]}}`
+	}
+	agent := `{"type":"tool_use","name":"Task","input":{"subagent_type":"reviewer"}}`
+	skill := `{"type":"tool_use","name":"Skill","input":{"skill":"pdf"}}`
+	mcp := `{"type":"tool_use","name":"mcp__old-docs__search","input":{}}`
+	writeJSONL(t, filepath.Join(dir, "s.jsonl"), []string{
+		line(time.Hour, agent), line(time.Hour, agent), line(time.Hour, agent),
+		line(2*24*time.Hour, skill),
+		// Used only before the 7d period.
+		line(20*24*time.Hour, mcp),
+	})
+
+	c := &Collector{HomeDir: home, Period: PeriodWeek, Config: []ConfigItem{
+		{Category: RankAgents, Name: "reviewer", Path: "/u/.claude/agents/reviewer.md"},
+		{Category: RankSkills, Name: "pdf", Path: "/u/.claude/skills/pdf/SKILL.md"},
+		{Category: RankMCP, Name: "old-docs", Path: "/p/.mcp.json"},
+		{Category: RankCommands, Name: "deploy", Path: "/p/.claude/commands/deploy.md"},
+	}}
+	data, err := c.Collect(ScopeAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, e := range data.Unused {
+		names = append(names, e.Name)
+	}
+	// Never used first, then idle longest; reviewer's 3 uses are above LowUsage.
+	want := []string{"deploy", "old-docs", "pdf"}
+	if len(names) != len(want) || names[0] != want[0] || names[1] != want[1] || names[2] != want[2] {
+		t.Fatalf("unused = %v, want %v", names, want)
+	}
+
+	deploy, docs, pdf := data.Unused[0], data.Unused[1], data.Unused[2]
+	if !deploy.LastUsed.IsZero() || deploy.Item.Path != "/p/.claude/commands/deploy.md" {
+		t.Errorf("deploy = %+v, want never used with its path", deploy)
+	}
+	if docs.Count != 0 || time.Since(docs.LastUsed) < 19*24*time.Hour {
+		t.Errorf("old-docs count/last = %d/%v, want 0 uses in the period, last used ~20 days ago", docs.Count, docs.LastUsed)
+	}
+	if pdf.Count != 1 || pdf.Grade == GradeF && pdf.Score == 0 {
+		t.Errorf("pdf = %+v, want 1 use graded like the Skills ranking", pdf)
+	}
+
+	// A live use updates the last-used time.
+	data.Add(Counts{Commands: map[string]int{"deploy": 1}})
+	for _, e := range data.Unused {
+		if e.Name == "deploy" && (e.Count != 1 || e.LastUsed.IsZero()) {
+			t.Errorf("deploy after Add = %d uses, last %v; want 1 use just now", e.Count, e.LastUsed)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package watcher
+
+import (
+	"hash/fnv"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxHashSize skips hashing files too large to be hand-written config.
+const maxHashSize = 1 << 20
+
+// fileState is the polled state of a single path.
+type fileState struct {
+	dir   bool
+	size  int64
+	mtime time.Time
+	hash  uint64 // FNV-1a of the content (0 for directories and oversized files)
+}
+
+// pollState records the state of every existing path covered by the targets:
+// the target itself and, for recursive directories, everything below it.
+func pollState(targets []Target) map[string]fileState {
+	state := make(map[string]fileState)
+	for _, t := range targets {
+		info, err := os.Stat(t.Path)
+		if err != nil {
+			continue
+		}
+		if !info.IsDir() {
+			state[t.Path] = statFile(t.Path, info)
+			continue
+		}
+		state[t.Path] = fileState{dir: true}
+		if !t.Recursive {
+			continue
+		}
+
+		dirs := make(map[string]bool)
+		walkDirs(t.Path, 0, dirs)
+		for dir := range dirs {
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				continue
+			}
+			for _, e := range entries {
+				if strings.HasPrefix(e.Name(), ".") {
+					continue
+				}
+				p := filepath.Join(dir, e.Name())
+				info, err := os.Stat(p)
+				if err != nil {
+					continue
+				}
+				if info.IsDir() {
+					state[p] = fileState{dir: true}
+				} else {
+					state[p] = statFile(p, info)
+				}
+			}
+		}
+	}
+	return state
+}
+
+// statFile builds the state of a regular file, hashing its content when small enough.
+// The hash catches edits that keep the size and land within the mtime resolution.
+func statFile(path string, info os.FileInfo) fileState {
+	fs := fileState{size: info.Size(), mtime: info.ModTime()}
+	if fs.size > maxHashSize {
+		return fs
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return fs
+	}
+	defer f.Close()
+	h := fnv.New64a()
+	if _, err := io.Copy(h, f); err == nil {
+		fs.hash = h.Sum64()
+	}
+	return fs
+}
+
+// diffStates compares two polls and returns the changed paths, sorted by path.
+func diffStates(before, after map[string]fileState) []Change {
+	var changes []Change
+	for p, a := range after {
+		b, ok := before[p]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Path: p, Op: OpCreated})
+		case a.dir != b.dir:
+			changes = append(changes, Change{Path: p, Op: OpModified})
+		case !a.dir && (a.size != b.size || !a.mtime.Equal(b.mtime) || a.hash != b.hash):
+			changes = append(changes, Change{Path: p, Op: OpModified})
+		}
+	}
+	for p := range before {
+		if _, ok := after[p]; !ok {
+			changes = append(changes, Change{Path: p, Op: OpRemoved})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
@@ -1,7 +1,14 @@
 package watcher
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -10,86 +17,387 @@ import (
 
 const debounceDelay = 300 * time.Millisecond
 
-// FileChangedMsg signals that a watched file has changed.
-type FileChangedMsg struct{}
+// maxWalkDepth bounds recursive directory walks (guards against symlink cycles).
+const maxWalkDepth = 16
+
+// Op describes what happened to a changed path.
+type Op int
+
+const (
+	OpModified Op = iota // Content written
+	OpCreated            // Path appeared
+	OpRemoved            // Path removed or renamed away
+)
+
+func (o Op) String() string {
+	switch o {
+	case OpCreated:
+		return "created"
+	case OpRemoved:
+		return "removed"
+	default:
+		return "modified"
+	}
+}
+
+// Change is a single changed path within a debounced batch.
+type Change struct {
+	Path string
+	Op   Op
+}
+
+// FileChangedMsg signals that watched files have changed.
+// Changes holds one entry per path, sorted by path.
+type FileChangedMsg struct {
+	Changes []Change
+}
+
+// Paths returns the changed paths.
+func (m FileChangedMsg) Paths() []string {
+	paths := make([]string, len(m.Changes))
+	for i, c := range m.Changes {
+		paths[i] = c.Path
+	}
+	return paths
+}
 
 // ErrorMsg signals that an error occurred during file watching.
 type ErrorMsg struct{ Err error }
 
-// Watcher wraps an fsnotify-based file watcher.
+// Target is a path to follow. It does not need to exist yet.
+type Target struct {
+	Path      string // Absolute file or directory path.
+	Recursive bool   // Whether every subdirectory is watched too.
+}
+
+// Mode is the change detection backend in use.
+type Mode int32
+
+const (
+	ModeNotify Mode = iota // fsnotify (inotify, kqueue, ...)
+	ModePoll               // Periodic stat + hash comparison
+)
+
+func (m Mode) String() string {
+	if m == ModePoll {
+		return "polling"
+	}
+	return "fsnotify"
+}
+
+// DefaultPollInterval is the polling interval used when Options.PollInterval is zero.
+const DefaultPollInterval = 2 * time.Second
+
+// Options configures a Watcher.
+type Options struct {
+	Poll         bool          // Always poll instead of trying fsnotify first.
+	PollInterval time.Duration // Interval between polls (DefaultPollInterval if zero).
+}
+
+// Watcher reports changes to a set of targets, using fsnotify when possible
+// and falling back to polling when fsnotify cannot be started or fails.
+// With fsnotify, the set of watched directories follows the targets: missing
+// targets are covered by their nearest existing ancestor until they are
+// created, and recursive targets gain and lose subdirectory watches as they change.
 type Watcher struct {
-	fsw  *fsnotify.Watcher
-	ch   chan tea.Msg
-	done chan struct{}
+	fsw      *fsnotify.Watcher // nil while polling
+	mode     atomic.Int32      // Current Mode; read from the UI goroutine.
+	interval time.Duration
+	ch       chan tea.Msg
+	done     chan struct{}
+	targets  []Target
+	watched  map[string]bool      // Directories currently registered with fsnotify.
+	files    map[string]fileState // Last polled state per path (polling mode).
 }
 
-// New creates a Watcher that monitors the given paths.
-// Non-existent paths are silently ignored.
-func New(paths []string) (*Watcher, error) {
-	fsw, err := fsnotify.NewWatcher()
-	if err != nil {
-		return nil, err
+// New creates a Watcher that follows the given targets.
+func New(targets []Target, opts Options) (*Watcher, error) {
+	w := &Watcher{
+		interval: opts.PollInterval,
+		ch:       make(chan tea.Msg, 1),
+		done:     make(chan struct{}),
+		targets:  targets,
+		watched:  make(map[string]bool),
+	}
+	if w.interval <= 0 {
+		w.interval = DefaultPollInterval
 	}
 
-	for _, p := range paths {
-		if _, err := os.Stat(p); err != nil {
-			continue // skip non-existent paths
+	if !opts.Poll {
+		if fsw, err := fsnotify.NewWatcher(); err == nil {
+			w.fsw = fsw
+			if err := w.sync(); err != nil {
+				fsw.Close()
+				w.fsw = nil
+			}
 		}
-		_ = fsw.Add(p)
 	}
-
-	w := &Watcher{
-		fsw:  fsw,
-		ch:   make(chan tea.Msg, 1),
-		done: make(chan struct{}),
+	if w.fsw == nil {
+		w.startPolling()
 	}
+
 	go w.loop()
 	return w, nil
 }
 
-// loop receives fsnotify events, debounces them, and forwards to ch.
+// Mode returns the backend currently in use.
+func (w *Watcher) Mode() Mode { return Mode(w.mode.Load()) }
+
+// Interval returns the polling interval.
+func (w *Watcher) Interval() time.Duration { return w.interval }
+
+// sync reconciles the registered watches with the directories the targets currently need.
+// It returns an error only when fsnotify runs out of watches or descriptors.
+func (w *Watcher) sync() error {
+	want := make(map[string]bool)
+	for _, t := range w.targets {
+		info, err := os.Stat(t.Path)
+		switch {
+		case err == nil && info.IsDir() && t.Recursive:
+			walkDirs(t.Path, 0, want)
+		case err == nil && info.IsDir():
+			want[t.Path] = true
+		default:
+			// Files are watched through their parent directory; missing paths
+			// through their nearest existing ancestor so creation is noticed.
+			if dir := existingAncestor(filepath.Dir(t.Path)); dir != "" {
+				want[dir] = true
+			}
+		}
+	}
+
+	for dir := range w.watched {
+		if !want[dir] {
+			_ = w.fsw.Remove(dir) // Fails harmlessly if fsnotify already dropped it.
+			delete(w.watched, dir)
+		}
+	}
+	for dir := range want {
+		if w.watched[dir] {
+			continue
+		}
+		err := w.fsw.Add(dir)
+		if err == nil {
+			w.watched[dir] = true
+			continue
+		}
+		if errors.Is(err, syscall.ENOSPC) || errors.Is(err, syscall.EMFILE) {
+			return err
+		}
+	}
+	return nil
+}
+
+// startPolling switches to polling, taking the current state as the baseline.
+func (w *Watcher) startPolling() {
+	if w.fsw != nil {
+		w.fsw.Close()
+		w.fsw = nil
+	}
+	w.watched = make(map[string]bool)
+	w.files = pollState(w.targets)
+	w.mode.Store(int32(ModePoll))
+}
+
+// walkDirs adds dir and its subdirectories (following symlinks) to out.
+func walkDirs(dir string, depth int, out map[string]bool) {
+	if depth > maxWalkDepth || out[dir] {
+		return
+	}
+	out[dir] = true
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		child := filepath.Join(dir, e.Name())
+		if info, err := os.Stat(child); err == nil && info.IsDir() {
+			walkDirs(child, depth+1, out)
+		}
+	}
+}
+
+// existingAncestor returns path or its nearest existing ancestor directory.
+func existingAncestor(path string) string {
+	for {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return ""
+		}
+		path = parent
+	}
+}
+
+// relevant reports whether an event on name can affect any target: the target
+// itself, a directory on the way to a target, or anything inside a recursive target.
+func (w *Watcher) relevant(name string) bool {
+	for _, t := range w.targets {
+		if name == t.Path || isWithin(t.Path, name) {
+			return true
+		}
+		if t.Recursive && isWithin(name, t.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+// isWithin reports whether path is strictly inside dir.
+func isWithin(path, dir string) bool {
+	return strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// loop receives fsnotify events or polls, debounces changes, and forwards the batch to ch.
 func (w *Watcher) loop() {
-	var timer *time.Timer
+	pending := make(map[string]Op)
+	timer := time.NewTimer(debounceDelay)
+	timer.Stop()
+	var ticker *time.Ticker
+	defer func() {
+		if ticker != nil {
+			ticker.Stop()
+		}
+	}()
 
 	for {
+		// Nil channels block forever, disabling the cases of the inactive backend.
+		var events <-chan fsnotify.Event
+		var errs <-chan error
+		var poll <-chan time.Time
+		if w.fsw != nil {
+			events, errs = w.fsw.Events, w.fsw.Errors
+		} else {
+			if ticker == nil {
+				ticker = time.NewTicker(w.interval)
+			}
+			poll = ticker.C
+		}
+
 		select {
 		case <-w.done:
-			if timer != nil {
-				timer.Stop()
+			timer.Stop()
+			if w.fsw != nil {
+				w.fsw.Close()
 			}
 			return
 
-		case event, ok := <-w.fsw.Events:
+		case event, ok := <-events:
 			if !ok {
 				return
 			}
 			// Filter only relevant events
-			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) == 0 {
+			op, ok := eventOp(event.Op)
+			if !ok || !w.relevant(event.Name) {
 				continue
 			}
+			// Directories may have appeared or disappeared: update the watch set.
+			if op != OpModified {
+				if err := w.sync(); err != nil {
+					w.fallback(err)
+				}
+			}
+			pending[event.Name] = mergeOp(pending, event.Name, op)
 			// Debounce: reset the timer
-			if timer != nil {
-				timer.Stop()
+			timer.Reset(debounceDelay)
+
+		case <-timer.C:
+			if w.flush(pending) {
+				pending = make(map[string]Op)
+			} else {
+				// A message is still pending: keep accumulating and retry later.
+				timer.Reset(debounceDelay)
+			}
+
+		case <-poll:
+			state := pollState(w.targets)
+			for _, c := range diffStates(w.files, state) {
+				pending[c.Path] = mergeOp(pending, c.Path, c.Op)
+			}
+			w.files = state
+			if len(pending) > 0 && w.flush(pending) {
+				pending = make(map[string]Op)
 			}
-			timer = time.AfterFunc(debounceDelay, func() {
-				select {
-				case w.ch <- FileChangedMsg{}:
-				default: // non-blocking: skip if a message is already pending
-				}
-			})
 
-		case err, ok := <-w.fsw.Errors:
+		case err, ok := <-errs:
 			if !ok {
 				return
 			}
-			select {
-			case w.ch <- ErrorMsg{Err: err}:
-			default:
+			// Overflowed queues mean lost events: polling is the reliable option.
+			if errors.Is(err, fsnotify.ErrEventOverflow) {
+				w.fallback(err)
+				continue
 			}
+			w.send(ErrorMsg{Err: err})
 		}
 	}
 }
 
+// fallback switches from fsnotify to polling after err and reports it.
+func (w *Watcher) fallback(err error) {
+	w.startPolling()
+	w.send(ErrorMsg{Err: fmt.Errorf("fsnotify failed, falling back to polling: %w", err)})
+}
+
+// flush sends the pending changes without blocking. Returns false if a message is still queued.
+func (w *Watcher) flush(pending map[string]Op) bool {
+	return w.send(FileChangedMsg{Changes: drain(pending)})
+}
+
+// send forwards msg without blocking. Returns false if a message is still queued.
+func (w *Watcher) send(msg tea.Msg) bool {
+	select {
+	case w.ch <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
+// eventOp maps an fsnotify operation to an Op. Chmod-only events are ignored.
+func eventOp(op fsnotify.Op) (Op, bool) {
+	switch {
+	case op&(fsnotify.Remove|fsnotify.Rename) != 0:
+		return OpRemoved, true
+	case op&fsnotify.Create != 0:
+		return OpCreated, true
+	case op&fsnotify.Write != 0:
+		return OpModified, true
+	}
+	return 0, false
+}
+
+// mergeOp folds a new event into the pending op for path: a file created and
+// then written is still "created", and one removed and recreated is "modified".
+func mergeOp(pending map[string]Op, path string, op Op) Op {
+	prev, ok := pending[path]
+	switch {
+	case !ok:
+		return op
+	case prev == OpCreated && op == OpModified:
+		return OpCreated
+	case prev == OpRemoved && op == OpCreated:
+		return OpModified
+	}
+	return op
+}
+
+// drain converts pending ops into a path-sorted change list.
+func drain(pending map[string]Op) []Change {
+	changes := make([]Change, 0, len(pending))
+	for p, op := range pending {
+		changes = append(changes, Change{Path: p, Op: op})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
 // WaitForChange returns a tea.Cmd that waits for the next file change message.
 func (w *Watcher) WaitForChange() tea.Cmd {
 	return func() tea.Msg {
@@ -104,5 +412,4 @@ func (w *Watcher) WaitForChange() tea.Cmd {
 // Close shuts down the watcher and releases its resources.
 func (w *Watcher) Close() {
 	close(w.done)
-	w.fsw.Close()
 }
@@ -7,6 +7,22 @@ import (
 	"time"
 )
 
+// expectChange waits for a FileChangedMsg (debounce 300ms + margin).
+func expectChange(t *testing.T, w *Watcher) FileChangedMsg {
+	t.Helper()
+	select {
+	case msg := <-w.ch:
+		fc, ok := msg.(FileChangedMsg)
+		if !ok {
+			t.Fatalf("expected FileChangedMsg, got %T", msg)
+		}
+		return fc
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout: FileChangedMsg not received")
+	}
+	return FileChangedMsg{}
+}
+
 func TestWatcher_DetectsFileChange(t *testing.T) {
 	dir := t.TempDir()
 	file := filepath.Join(dir, "test.json")
@@ -15,7 +31,7 @@ func TestWatcher_DetectsFileChange(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	w, err := New([]string{file, dir})
+	w, err := New([]Target{{Path: file}, {Path: dir, Recursive: true}}, Options{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -25,15 +41,45 @@ func TestWatcher_DetectsFileChange(t *testing.T) {
 	if err := os.WriteFile(file, []byte(`{"a":2}`), 0644); err != nil {
 		t.Fatal(err)
 	}
+	msg := expectChange(t, w)
+	if len(msg.Changes) != 1 || msg.Changes[0].Path != file || msg.Changes[0].Op != OpModified {
+		t.Errorf("Changes = %+v, want [{%s modified}]", msg.Changes, file)
+	}
+}
 
-	// Debounce (300ms) + margin
-	select {
-	case msg := <-w.ch:
-		if _, ok := msg.(FileChangedMsg); !ok {
-			t.Fatalf("expected FileChangedMsg, got %T", msg)
+func TestWatcher_BatchesChangesPerPath(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.json")
+	b := filepath.Join(dir, "b.json")
+	if err := os.WriteFile(a, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := New([]Target{{Path: a}, {Path: b}}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// Several writes within the debounce window collapse into one change per path.
+	for i := 0; i < 3; i++ {
+		if err := os.WriteFile(a, []byte(`{"n":1}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(b, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := expectChange(t, w)
+	want := []Change{{Path: a, Op: OpModified}, {Path: b, Op: OpCreated}}
+	if len(msg.Changes) != len(want) {
+		t.Fatalf("Changes = %+v, want %+v", msg.Changes, want)
+	}
+	for i := range want {
+		if msg.Changes[i] != want[i] {
+			t.Errorf("Changes[%d] = %+v, want %+v", i, msg.Changes[i], want[i])
 		}
-	case <-time.After(2 * time.Second):
-		t.Fatal("timeout: FileChangedMsg not received")
 	}
 }
 
@@ -41,7 +87,7 @@ func TestWatcher_SkipsMissingPaths(t *testing.T) {
 	existing := t.TempDir()
 	missing := filepath.Join(existing, "no_such_dir")
 
-	w, err := New([]string{missing, existing})
+	w, err := New([]Target{{Path: missing, Recursive: true}, {Path: existing}}, Options{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -52,3 +98,135 @@ func TestWatcher_SkipsMissingPaths(t *testing.T) {
 		t.Fatal("fsw should not be nil")
 	}
 }
+
+func TestWatcher_FollowsCreatedDirectory(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, ".claude", "settings.json")
+
+	w, err := New([]Target{{Path: file}}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	if err := os.Mkdir(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+	expectChange(t, w)
+
+	if err := os.WriteFile(file, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	expectChange(t, w)
+}
+
+func TestWatcher_WatchesNestedSkillFolders(t *testing.T) {
+	skills := filepath.Join(t.TempDir(), "skills")
+	if err := os.Mkdir(skills, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := New([]Target{{Path: skills, Recursive: true}}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	skillDir := filepath.Join(skills, "my-skill")
+	if err := os.Mkdir(skillDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	expectChange(t, w)
+
+	if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("# my-skill"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	expectChange(t, w)
+}
+
+func TestWatcher_IgnoresUnrelatedFiles(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "settings.json")
+	if err := os.WriteFile(file, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := New([]Target{{Path: file}}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	if err := os.WriteFile(filepath.Join(dir, "history.jsonl"), []byte("{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case msg := <-w.ch:
+		t.Fatalf("unexpected message %T for unrelated file", msg)
+	case <-time.After(600 * time.Millisecond):
+	}
+}
+
+func TestWatcher_PollingDetectsChanges(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "settings.json")
+	skills := filepath.Join(dir, "skills")
+	if err := os.WriteFile(file, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(skills, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := New([]Target{{Path: file}, {Path: skills, Recursive: true}}, Options{Poll: true, PollInterval: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	if w.Mode() != ModePoll {
+		t.Fatalf("Mode() = %v, want polling", w.Mode())
+	}
+
+	if err := os.WriteFile(file, []byte(`{"model":"opus"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	msg := expectChange(t, w)
+	if len(msg.Changes) != 1 || msg.Changes[0] != (Change{Path: file, Op: OpModified}) {
+		t.Errorf("Changes = %+v, want modified %s", msg.Changes, file)
+	}
+
+	skill := filepath.Join(skills, "review", "SKILL.md")
+	if err := os.MkdirAll(filepath.Dir(skill), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(skill, []byte("# review"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	msg = expectChange(t, w)
+	found := false
+	for _, c := range msg.Changes {
+		if c.Path == skill && c.Op == OpCreated {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Changes = %+v, want created %s", msg.Changes, skill)
+	}
+}
+
+func TestDiffStates_DetectsSameSizeEdit(t *testing.T) {
+	mtime := time.Now()
+	before := map[string]fileState{"/a": {size: 2, mtime: mtime, hash: 1}}
+	after := map[string]fileState{"/a": {size: 2, mtime: mtime, hash: 2}, "/b": {dir: true}}
+
+	got := diffStates(before, after)
+	want := []Change{{Path: "/a", Op: OpModified}, {Path: "/b", Op: OpCreated}}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("diffStates() = %+v, want %+v", got, want)
+	}
+
+	if got := diffStates(after, nil); len(got) != 2 || got[0].Op != OpRemoved {
+		t.Errorf("diffStates() removal = %+v", got)
+	}
+}